@@ -1,5 +1,7 @@
 package runscope
 
+import "context"
+
 // Account represents a Runscope Account
 type Account struct {
 	Name      string  `json:"name"`
@@ -13,9 +15,14 @@ type Account struct {
 // GetAccount returns the account associated with the token used
 // to perform the request
 func (client *Client) GetAccount() (Account, error) {
+	return client.GetAccountWithContext(context.Background())
+}
+
+// GetAccountWithContext is the same as GetAccount, bound to the supplied context
+func (client *Client) GetAccountWithContext(ctx context.Context) (Account, error) {
 	var account = Account{}
 
-	content, err := client.Get("account")
+	content, err := client.GetWithContext(ctx, "account")
 	if err != nil {
 		return account, err
 	}
@@ -0,0 +1,267 @@
+package runscope
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Source is the part of a response an assertion or variable reads from
+type Source string
+
+// Sources supported by Runscope assertions and variables
+const (
+	SourceStatus       Source = "response_status"
+	SourceHeaders      Source = "response_headers"
+	SourceJSON         Source = "response_json"
+	SourceXML          Source = "response_xml"
+	SourceText         Source = "response_text"
+	SourceResponseTime Source = "response_time"
+	SourceResponseSize Source = "response_size"
+	SourceCookies      Source = "response_cookies"
+)
+
+// Comparison is the operator an assertion applies to its source
+type Comparison string
+
+// Comparisons supported by Runscope assertions
+const (
+	ComparisonIsEmpty            Comparison = "is_empty"
+	ComparisonIsNotEmpty         Comparison = "is_not_empty"
+	ComparisonEqual              Comparison = "equal"
+	ComparisonNotEqual           Comparison = "not_equal"
+	ComparisonContains           Comparison = "contains"
+	ComparisonDoesNotContain     Comparison = "does_not_contain"
+	ComparisonIsNumber           Comparison = "is_a_number"
+	ComparisonIsNull             Comparison = "is_null"
+	ComparisonEqualNumber        Comparison = "equal_number"
+	ComparisonLessThan           Comparison = "is_less_than"
+	ComparisonLessThanOrEqual    Comparison = "is_less_than_or_equal"
+	ComparisonGreaterThan        Comparison = "is_greater_than"
+	ComparisonGreaterThanOrEqual Comparison = "is_greater_than_or_equal"
+	ComparisonHasKey             Comparison = "has_key"
+	ComparisonHasValue           Comparison = "has_value"
+)
+
+// comparisonRule describes which values and sources a comparison accepts
+type comparisonRule struct {
+	needsValue bool
+	numeric    bool
+	// jsonOnly comparisons inspect objects and arrays
+	jsonOnly bool
+}
+
+var comparisonRules = map[Comparison]comparisonRule{
+	ComparisonIsEmpty:            {},
+	ComparisonIsNotEmpty:         {},
+	ComparisonEqual:              {needsValue: true},
+	ComparisonNotEqual:           {needsValue: true},
+	ComparisonContains:           {needsValue: true},
+	ComparisonDoesNotContain:     {needsValue: true},
+	ComparisonIsNumber:           {},
+	ComparisonIsNull:             {},
+	ComparisonEqualNumber:        {needsValue: true, numeric: true},
+	ComparisonLessThan:           {needsValue: true, numeric: true},
+	ComparisonLessThanOrEqual:    {needsValue: true, numeric: true},
+	ComparisonGreaterThan:        {needsValue: true, numeric: true},
+	ComparisonGreaterThanOrEqual: {needsValue: true, numeric: true},
+	ComparisonHasKey:             {needsValue: true, jsonOnly: true},
+	ComparisonHasValue:           {needsValue: true, jsonOnly: true},
+}
+
+// numericSources only produce numbers
+var numericSources = map[Source]bool{
+	SourceStatus:       true,
+	SourceResponseTime: true,
+	SourceResponseSize: true,
+}
+
+// propertySources are the sources reading a property of the response,
+// mapped to whether the property is required
+var propertySources = map[Source]bool{
+	SourceHeaders: true,
+	SourceJSON:    false,
+	SourceXML:     true,
+}
+
+// ValidateAssertion checks that the source, property, comparison and
+// value of an assertion are a combination Runscope accepts
+func ValidateAssertion(assertion Assertion) error {
+	source := Source(assertion.Source)
+	comparison := Comparison(assertion.Comparison)
+
+	required, hasProperty := propertySources[source]
+	if !hasProperty && !numericSources[source] && source != SourceText {
+		return fmt.Errorf("Assertion source %q is not supported", assertion.Source)
+	}
+	if required && assertion.Property == "" {
+		return fmt.Errorf("Assertion source %q requires a property", assertion.Source)
+	}
+	if !hasProperty && assertion.Property != "" {
+		return fmt.Errorf("Assertion source %q does not take a property", assertion.Source)
+	}
+
+	rule, ok := comparisonRules[comparison]
+	if !ok {
+		return fmt.Errorf("Assertion comparison %q is not supported", assertion.Comparison)
+	}
+	if rule.jsonOnly && source != SourceJSON {
+		return fmt.Errorf("Assertion comparison %q only applies to %q", comparison, SourceJSON)
+	}
+	if numericSources[source] && !rule.numeric && rule.needsValue && comparison != ComparisonEqual && comparison != ComparisonNotEqual {
+		return fmt.Errorf("Assertion comparison %q does not apply to numeric source %q", comparison, source)
+	}
+
+	if !rule.needsValue {
+		return nil
+	}
+	if assertion.Value == nil || assertion.Value == "" {
+		return fmt.Errorf("Assertion comparison %q requires a value", comparison)
+	}
+	if (rule.numeric || numericSources[source]) && !isNumeric(assertion.Value) {
+		return fmt.Errorf("Assertion comparison %q on %q requires a numeric value, got %v", comparison, source, assertion.Value)
+	}
+	return nil
+}
+
+// isNumeric reports whether a value is a number, a numeric string or a
+// template which will be substituted by Runscope
+func isNumeric(value interface{}) bool {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	case string:
+		if strings.Contains(v, "{{") {
+			return true
+		}
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	}
+	return false
+}
+
+// AssertionBuilder builds an Assertion for a given source, e.g.
+// AssertJSON("data.id").NotEmpty() or AssertStatus().Equals(200)
+type AssertionBuilder struct {
+	source   Source
+	property string
+}
+
+// AssertStatus builds assertions on the response status code
+func AssertStatus() AssertionBuilder {
+	return AssertionBuilder{source: SourceStatus}
+}
+
+// AssertHeader builds assertions on a response header
+func AssertHeader(name string) AssertionBuilder {
+	return AssertionBuilder{source: SourceHeaders, property: name}
+}
+
+// AssertJSON builds assertions on a property of a JSON response body,
+// or the whole body when path is empty
+func AssertJSON(path string) AssertionBuilder {
+	return AssertionBuilder{source: SourceJSON, property: path}
+}
+
+// AssertXML builds assertions on an XPath expression of an XML response body
+func AssertXML(xpath string) AssertionBuilder {
+	return AssertionBuilder{source: SourceXML, property: xpath}
+}
+
+// AssertText builds assertions on the raw response body
+func AssertText() AssertionBuilder {
+	return AssertionBuilder{source: SourceText}
+}
+
+// AssertResponseTime builds assertions on the response time in milliseconds
+func AssertResponseTime() AssertionBuilder {
+	return AssertionBuilder{source: SourceResponseTime}
+}
+
+// AssertResponseSize builds assertions on the response size in bytes
+func AssertResponseSize() AssertionBuilder {
+	return AssertionBuilder{source: SourceResponseSize}
+}
+
+func (builder AssertionBuilder) build(comparison Comparison, value interface{}) Assertion {
+	return Assertion{
+		Source:     string(builder.source),
+		Property:   builder.property,
+		Comparison: string(comparison),
+		Value:      value,
+	}
+}
+
+// Equals asserts the source equals value. Numbers are compared
+// numerically.
+func (builder AssertionBuilder) Equals(value interface{}) Assertion {
+	if _, ok := value.(string); !ok && isNumeric(value) {
+		return builder.build(ComparisonEqualNumber, value)
+	}
+	return builder.build(ComparisonEqual, value)
+}
+
+// NotEquals asserts the source does not equal value
+func (builder AssertionBuilder) NotEquals(value interface{}) Assertion {
+	return builder.build(ComparisonNotEqual, value)
+}
+
+// Contains asserts the source contains value
+func (builder AssertionBuilder) Contains(value string) Assertion {
+	return builder.build(ComparisonContains, value)
+}
+
+// DoesNotContain asserts the source does not contain value
+func (builder AssertionBuilder) DoesNotContain(value string) Assertion {
+	return builder.build(ComparisonDoesNotContain, value)
+}
+
+// IsEmpty asserts the source is empty
+func (builder AssertionBuilder) IsEmpty() Assertion {
+	return builder.build(ComparisonIsEmpty, nil)
+}
+
+// NotEmpty asserts the source is not empty
+func (builder AssertionBuilder) NotEmpty() Assertion {
+	return builder.build(ComparisonIsNotEmpty, nil)
+}
+
+// IsNull asserts the source is null
+func (builder AssertionBuilder) IsNull() Assertion {
+	return builder.build(ComparisonIsNull, nil)
+}
+
+// IsNumber asserts the source is a number
+func (builder AssertionBuilder) IsNumber() Assertion {
+	return builder.build(ComparisonIsNumber, nil)
+}
+
+// LessThan asserts the source is a number less than value
+func (builder AssertionBuilder) LessThan(value float64) Assertion {
+	return builder.build(ComparisonLessThan, value)
+}
+
+// LessThanOrEqual asserts the source is a number less than or equal to value
+func (builder AssertionBuilder) LessThanOrEqual(value float64) Assertion {
+	return builder.build(ComparisonLessThanOrEqual, value)
+}
+
+// GreaterThan asserts the source is a number greater than value
+func (builder AssertionBuilder) GreaterThan(value float64) Assertion {
+	return builder.build(ComparisonGreaterThan, value)
+}
+
+// GreaterThanOrEqual asserts the source is a number greater than or equal to value
+func (builder AssertionBuilder) GreaterThanOrEqual(value float64) Assertion {
+	return builder.build(ComparisonGreaterThanOrEqual, value)
+}
+
+// HasKey asserts the JSON object has the given key
+func (builder AssertionBuilder) HasKey(key string) Assertion {
+	return builder.build(ComparisonHasKey, key)
+}
+
+// HasValue asserts the JSON array or object contains value
+func (builder AssertionBuilder) HasValue(value interface{}) Assertion {
+	return builder.build(ComparisonHasValue, value)
+}
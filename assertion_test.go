@@ -0,0 +1,81 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestAssertionBuilder(t *testing.T) {
+	tests := []struct {
+		assertion Assertion
+		want      Assertion
+	}{
+		{AssertStatus().Equals(200), Assertion{Source: "response_status", Comparison: "equal_number", Value: 200}},
+		{AssertJSON("data.id").NotEmpty(), Assertion{Source: "response_json", Property: "data.id", Comparison: "is_not_empty"}},
+		{AssertJSON("data.name").Equals("Grace"), Assertion{Source: "response_json", Property: "data.name", Comparison: "equal", Value: "Grace"}},
+		{AssertHeader("Content-Type").Contains("json"), Assertion{Source: "response_headers", Property: "Content-Type", Comparison: "contains", Value: "json"}},
+		{AssertResponseTime().LessThan(500), Assertion{Source: "response_time", Comparison: "is_less_than", Value: float64(500)}},
+		{AssertJSON("data").HasKey("id"), Assertion{Source: "response_json", Property: "data", Comparison: "has_key", Value: "id"}},
+		{AssertXML("/user/name").IsNull(), Assertion{Source: "response_xml", Property: "/user/name", Comparison: "is_null"}},
+		{AssertText().DoesNotContain("error"), Assertion{Source: "response_text", Comparison: "does_not_contain", Value: "error"}},
+		{AssertResponseSize().GreaterThanOrEqual(10), Assertion{Source: "response_size", Comparison: "is_greater_than_or_equal", Value: float64(10)}},
+	}
+
+	for _, test := range tests {
+		testResponseData(t, test.assertion, test.want)
+		if err := ValidateAssertion(test.assertion); err != nil {
+			t.Errorf("ValidateAssertion(%+v) returned error: %v", test.assertion, err)
+		}
+	}
+}
+
+func TestValidateAssertion(t *testing.T) {
+	invalid := []Assertion{
+		{Source: "response_body", Comparison: "equal", Value: "a"},
+		{Source: "response_json", Comparison: "is_equal", Value: "a"},
+		{Source: "response_headers", Comparison: "is_not_empty"},
+		{Source: "response_status", Property: "code", Comparison: "equal_number", Value: 200},
+		{Source: "response_status", Comparison: "equal_number", Value: "OK"},
+		{Source: "response_status", Comparison: "contains", Value: "20"},
+		{Source: "response_json", Property: "id", Comparison: "equal"},
+		{Source: "response_json", Property: "id", Comparison: "is_less_than", Value: "ten"},
+		{Source: "response_text", Comparison: "has_key", Value: "id"},
+		AssertHeader("").NotEmpty(),
+	}
+	for _, assertion := range invalid {
+		if err := ValidateAssertion(assertion); err == nil {
+			t.Errorf("ValidateAssertion(%+v) should return an error", assertion)
+		}
+	}
+
+	valid := []Assertion{
+		{Source: "response_status", Comparison: "equal_number", Value: "{{expected_status}}"},
+		{Source: "response_status", Comparison: "equal", Value: "200"},
+		{Source: "response_json", Comparison: "is_not_empty"},
+	}
+	for _, assertion := range valid {
+		if err := ValidateAssertion(assertion); err != nil {
+			t.Errorf("ValidateAssertion(%+v) returned error: %v", assertion, err)
+		}
+	}
+}
+
+func TestNewStepValidatesAssertions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	step := Step{
+		StepType:   "request",
+		Assertions: []Assertion{AssertStatus().Equals("OK")},
+	}
+	if _, err := client.NewStep("1", "1", step); err == nil {
+		t.Error("NewStep should reject invalid assertions before sending them")
+	}
+	if _, err := client.UpdateStep("1", "1", "1", step); err == nil {
+		t.Error("UpdateStep should reject invalid assertions before sending them")
+	}
+
+	condition := ConditionStep{Steps: StepDefinitions{RequestStep{Assertions: step.Assertions}}}
+	if _, err := client.NewStepDefinition("1", "1", condition); err == nil {
+		t.Error("NewStepDefinition should reject invalid nested assertions")
+	}
+}
@@ -0,0 +1,139 @@
+package runscope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultConcurrency is the number of triggers sent at once by
+// BatchTrigger when no concurrency is given
+const DefaultConcurrency = 4
+
+// BatchTriggerRequest describes test runs to start across many buckets
+// and tests
+type BatchTriggerRequest struct {
+	// Buckets are triggered with their TriggerURL, running every test
+	Buckets []Bucket
+	// Tests are triggered with their TriggerURL
+	Tests []Test
+	// EnvironmentIDs triggers every bucket and test once per
+	// environment. The default environments are used when it is empty.
+	EnvironmentIDs []string
+	// Options holds the regions, notification setting and variables
+	// applied to every trigger. Its TriggerID, Bucket and EnvironmentID
+	// are ignored.
+	Options TriggerRequest
+	// Concurrency is the number of triggers sent at once. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+}
+
+// BatchTrigger is the outcome of a single trigger in a batch
+type BatchTrigger struct {
+	// Name is the name of the bucket or test triggered
+	Name    string
+	Request TriggerRequest
+	Result  TriggerResult
+	Error   error
+}
+
+// BatchTriggerReport combines the outcome of every trigger in a batch
+type BatchTriggerReport struct {
+	Triggers []BatchTrigger
+	// Runs holds the runs started by every trigger
+	Runs        []TestRun
+	RunsFailed  int
+	RunsStarted int
+	RunsTotal   int
+	// TriggersFailed counts the triggers which returned an error
+	TriggersFailed int
+}
+
+// BatchTrigger triggers every bucket and test of a batch concurrently
+// and returns a combined report. The error joins the errors of every
+// failed trigger; the report is complete either way.
+func (client *Client) BatchTrigger(ctx context.Context, batch BatchTriggerRequest) (BatchTriggerReport, error) {
+	report := BatchTriggerReport{Runs: []TestRun{}}
+
+	triggers, err := batch.triggers()
+	if err != nil {
+		return report, err
+	}
+
+	concurrency := batch.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				triggers[i].Result, triggers[i].Error = client.TriggerTestsWithContext(ctx, triggers[i].Request)
+			}
+		}()
+	}
+	for i := range triggers {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	var errs []error
+	for _, trigger := range triggers {
+		if trigger.Error != nil {
+			report.TriggersFailed++
+			errs = append(errs, fmt.Errorf("%s: %w", trigger.Name, trigger.Error))
+		}
+		report.Runs = append(report.Runs, trigger.Result.Runs...)
+		report.RunsFailed += trigger.Result.RunsFailed
+		report.RunsStarted += trigger.Result.RunsStarted
+		report.RunsTotal += trigger.Result.RunsTotal
+	}
+	report.Triggers = triggers
+	return report, errors.Join(errs...)
+}
+
+// triggers returns a trigger for every bucket and test of the batch in
+// every environment
+func (batch BatchTriggerRequest) triggers() ([]BatchTrigger, error) {
+	type target struct {
+		name string
+		url  string
+	}
+
+	targets := []target{}
+	for _, bucket := range batch.Buckets {
+		targets = append(targets, target{bucket.Name, bucket.TriggerURL})
+	}
+	for _, test := range batch.Tests {
+		targets = append(targets, target{test.Name, test.TriggerURL})
+	}
+
+	environmentIDs := batch.EnvironmentIDs
+	if len(environmentIDs) == 0 {
+		environmentIDs = []string{""}
+	}
+
+	triggers := []BatchTrigger{}
+	for _, t := range targets {
+		parsed, err := ParseTriggerURL(t.url)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.name, err)
+		}
+
+		for _, environmentID := range environmentIDs {
+			request := batch.Options
+			request.TriggerID = parsed.TriggerID
+			request.Bucket = parsed.Bucket
+			request.EnvironmentID = environmentID
+			triggers = append(triggers, BatchTrigger{Name: t.name, Request: request})
+		}
+	}
+	return triggers, nil
+}
@@ -0,0 +1,80 @@
+package runscope
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestBatchTrigger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	requests := map[string]int{}
+	mux.HandleFunc("/radar/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path+"?"+r.URL.RawQuery]++
+		mu.Unlock()
+
+		if r.URL.Path == "/radar/broken/trigger" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"runs": [{"test_run_id": %q}], "runs_failed": 1, "runs_started": 1, "runs_total": 2}, "error": null, "meta": {"status": "success"}}`, r.URL.Path)
+	})
+
+	report, err := client.BatchTrigger(context.Background(), BatchTriggerRequest{
+		Buckets: []Bucket{
+			{Name: "Mobile Apps", TriggerURL: "https://api.runscope.com/radar/bucket/b1/trigger"},
+		},
+		Tests: []Test{
+			{Name: "Sample Test", TriggerURL: "https://api.runscope.com/radar/t1/trigger"},
+			{Name: "Broken Test", TriggerURL: "https://api.runscope.com/radar/broken/trigger"},
+		},
+		EnvironmentIDs: []string{"production", "staging"},
+		Options:        TriggerRequest{Regions: []string{"us1"}},
+		Concurrency:    2,
+	})
+	if err == nil {
+		t.Error("BatchTrigger should return an error when a trigger fails")
+	}
+
+	if len(report.Triggers) != 6 {
+		t.Fatalf("BatchTrigger returned %d triggers, want 6", len(report.Triggers))
+	}
+	if report.TriggersFailed != 2 || !IsNotFound(report.Triggers[4].Error) {
+		t.Errorf("BatchTrigger failed triggers: %d, last error %v", report.TriggersFailed, report.Triggers[4].Error)
+	}
+	if report.RunsStarted != 4 || report.RunsFailed != 4 || report.RunsTotal != 8 || len(report.Runs) != 4 {
+		t.Errorf("BatchTrigger report: %+v", report)
+	}
+	if report.Triggers[0].Name != "Mobile Apps" || report.Triggers[0].Result.Runs[0].TestRunID != "/radar/bucket/b1/trigger" {
+		t.Errorf("BatchTrigger first trigger: %+v", report.Triggers[0])
+	}
+
+	for _, want := range []string{
+		"/radar/bucket/b1/trigger?runscope_environment=production&runscope_region=us1",
+		"/radar/bucket/b1/trigger?runscope_environment=staging&runscope_region=us1",
+		"/radar/t1/trigger?runscope_environment=production&runscope_region=us1",
+		"/radar/t1/trigger?runscope_environment=staging&runscope_region=us1",
+	} {
+		if requests[want] != 1 {
+			t.Errorf("Requests for %s: %d, want 1", want, requests[want])
+		}
+	}
+}
+
+func TestBatchTriggerInvalidURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.BatchTrigger(context.Background(), BatchTriggerRequest{
+		Tests: []Test{{Name: "Sample Test", TriggerURL: "https://api.runscope.com/buckets/abc"}},
+	})
+	if err == nil {
+		t.Error("BatchTrigger should reject invalid trigger URLs")
+	}
+}
@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -28,9 +29,14 @@ type NewBucketRequest struct {
 
 // ListBuckets returns all buckets for a given account
 func (client *Client) ListBuckets() ([]Bucket, error) {
+	return client.ListBucketsWithContext(context.Background())
+}
+
+// ListBucketsWithContext is the same as ListBuckets, bound to the supplied context
+func (client *Client) ListBucketsWithContext(ctx context.Context) ([]Bucket, error) {
 	var buckets = []Bucket{}
 
-	content, err := client.Get("buckets")
+	content, err := client.GetWithContext(ctx, "buckets")
 	if err != nil {
 		return buckets, err
 	}
@@ -41,10 +47,15 @@ func (client *Client) ListBuckets() ([]Bucket, error) {
 
 // GetBucket fetches the details for a single bucket specified by the Bucket Key
 func (client *Client) GetBucket(bucketKey string) (Bucket, error) {
+	return client.GetBucketWithContext(context.Background(), bucketKey)
+}
+
+// GetBucketWithContext is the same as GetBucket, bound to the supplied context
+func (client *Client) GetBucketWithContext(ctx context.Context, bucketKey string) (Bucket, error) {
 	var bucket = Bucket{}
 
 	path := fmt.Sprintf("buckets/%s", bucketKey)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return bucket, err
 	}
@@ -55,6 +66,11 @@ func (client *Client) GetBucket(bucketKey string) (Bucket, error) {
 
 // NewBucket creates a new Runscope bucket
 func (client *Client) NewBucket(newBucketRequest *NewBucketRequest) (Bucket, error) {
+	return client.NewBucketWithContext(context.Background(), newBucketRequest)
+}
+
+// NewBucketWithContext is the same as NewBucket, bound to the supplied context
+func (client *Client) NewBucketWithContext(ctx context.Context, newBucketRequest *NewBucketRequest) (Bucket, error) {
 	var bucket = Bucket{}
 
 	data, err := json.Marshal(newBucketRequest)
@@ -62,7 +78,7 @@ func (client *Client) NewBucket(newBucketRequest *NewBucketRequest) (Bucket, err
 		return bucket, err
 	}
 
-	content, err := client.Post("buckets", data)
+	content, err := client.PostWithContext(ctx, "buckets", data)
 	if err != nil {
 		return bucket, err
 	}
@@ -73,6 +89,11 @@ func (client *Client) NewBucket(newBucketRequest *NewBucketRequest) (Bucket, err
 
 // DeleteBucket removes a bucket from the account
 func (client *Client) DeleteBucket(bucketKey string) error {
+	return client.DeleteBucketWithContext(context.Background(), bucketKey)
+}
+
+// DeleteBucketWithContext is the same as DeleteBucket, bound to the supplied context
+func (client *Client) DeleteBucketWithContext(ctx context.Context, bucketKey string) error {
 	path := fmt.Sprintf("buckets/%s", bucketKey)
-	return client.Delete(path)
+	return client.DeleteWithContext(ctx, path)
 }
@@ -0,0 +1,208 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+)
+
+// CassetteMode selects whether a Recorder records or replays interactions
+type CassetteMode int
+
+const (
+	// ModeRecord sends requests to Runscope and records every interaction
+	ModeRecord CassetteMode = iota
+	// ModeReplay serves recorded interactions without any network access
+	ModeReplay
+)
+
+// Cassette is a recorded set of HTTP interactions with the Runscope API
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request stored in a cassette. The bearer token is
+// never recorded.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response stored in a cassette
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is a http.RoundTripper recording interactions to a cassette
+// file or replaying them from one. Use it as Options.Transport.
+type Recorder struct {
+	mode      CassetteMode
+	path      string
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder creates a Recorder for the cassette at path. In ModeReplay
+// the cassette is loaded from path; in ModeRecord requests are sent with
+// transport, or the default transport when it is nil, and the cassette is
+// written to path by Save.
+func NewRecorder(path string, mode CassetteMode, transport http.RoundTripper) (*Recorder, error) {
+	recorder := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: transport,
+	}
+	if recorder.transport == nil {
+		recorder.transport = NewTransport()
+	}
+
+	if mode == ModeReplay {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(content, &recorder.cassette); err != nil {
+			return nil, err
+		}
+		recorder.used = make([]bool, len(recorder.cassette.Interactions))
+	}
+	return recorder, nil
+}
+
+// Save writes the recorded interactions to the cassette file
+func (recorder *Recorder) Save() error {
+	if recorder.mode != ModeRecord {
+		return nil
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	content, err := json.MarshalIndent(recorder.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(recorder.path, append(content, '\n'), 0644)
+}
+
+// RoundTrip implements http.RoundTripper
+func (recorder *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := bearerToken(req.Header)
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  redactString(req.URL.RawQuery, token),
+		Body:   redactBody(body, token),
+	}
+
+	if recorder.mode == ModeReplay {
+		return recorder.replay(req, recorded)
+	}
+	return recorder.record(req, recorded, token)
+}
+
+func (recorder *Recorder) record(req *http.Request, recorded RecordedRequest, token string) (*http.Response, error) {
+	res, err := recorder.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.cassette.Interactions = append(recorder.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Body:       redactBody(body, token),
+		},
+	})
+	return res, nil
+}
+
+// replay serves the first unused interaction matching the request
+func (recorder *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	for i, interaction := range recorder.cassette.Interactions {
+		if recorder.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		recorder.used[i] = true
+
+		header := interaction.Response.Header
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, errors.New("No recorded interaction matches " + recorded.Method + " " + recorded.Path)
+}
+
+// matches compares requests by method, path, query parameters and body,
+// ignoring the order of query parameters and JSON formatting
+func (recorded RecordedRequest) matches(other RecordedRequest) bool {
+	if recorded.Method != other.Method || recorded.Path != other.Path {
+		return false
+	}
+
+	query, err := url.ParseQuery(recorded.Query)
+	if err != nil {
+		return false
+	}
+	otherQuery, err := url.ParseQuery(other.Query)
+	if err != nil || !reflect.DeepEqual(query, otherQuery) {
+		return false
+	}
+
+	return redactBody([]byte(recorded.Body), "") == other.Body
+}
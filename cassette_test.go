@@ -0,0 +1,92 @@
+package runscope
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	setup()
+
+	handleGet(t, "/buckets/1", http.StatusOK, `{"data": {"name": "Mobile Apps", "key": "1", "auth_token": "bucket-secret"}, "error": null, "meta": {"status": "success"}}`)
+	mux.HandleFunc("/buckets/1/tests", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"data": {"name": "Sample Test", "id": "2"}, "error": null, "meta": {"status": "success"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [], "error": null, "meta": {"status": "success"}}`)
+	})
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := NewRecorder(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	client := NewClient(Options{BaseURL: server.URL, Token: "secret-token", Transport: recorder})
+	bucket, err := client.GetBucket("1")
+	if err != nil {
+		t.Fatalf("GetBucket returned error: %v", err)
+	}
+	test, err := client.NewTest("1", NewTestRequest{Name: "Sample Test"})
+	if err != nil {
+		t.Fatalf("NewTest returned error: %v", err)
+	}
+	if _, err := client.ListTests("1", ListTestOptions{Count: 10}); err != nil {
+		t.Fatalf("ListTests returned error: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	baseURL := server.URL
+	teardown()
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cassette was not written: %v", err)
+	}
+	if strings.Contains(string(content), "secret-token") {
+		t.Errorf("Cassette contains the token: %s", content)
+	}
+	if strings.Contains(string(content), "bucket-secret") {
+		t.Errorf("Cassette contains the bucket auth token: %s", content)
+	}
+
+	replayer, err := NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	client = NewClient(Options{BaseURL: baseURL, Token: "other-token", Transport: replayer})
+
+	replayedTest, err := client.NewTest("1", NewTestRequest{Name: "Sample Test"})
+	if err != nil {
+		t.Fatalf("Replayed NewTest returned error: %v", err)
+	}
+	testResponseData(t, replayedTest, test)
+
+	replayedBucket, err := client.GetBucket("1")
+	if err != nil {
+		t.Fatalf("Replayed GetBucket returned error: %v", err)
+	}
+	if replayedBucket.Name != bucket.Name || replayedBucket.AuthToken != Redacted {
+		t.Errorf("Replayed bucket: %+v", replayedBucket)
+	}
+
+	if _, err := client.ListTests("1", ListTestOptions{Count: 10}); err != nil {
+		t.Errorf("Replayed ListTests returned error: %v", err)
+	}
+	if _, err := client.ListTests("1", ListTestOptions{Count: 20}); err == nil {
+		t.Error("ListTests with a different query should not match the cassette")
+	}
+	if _, err := client.GetBucket("1"); err == nil {
+		t.Error("Interactions should only be replayed once")
+	}
+	if _, err := client.NewTest("1", NewTestRequest{Name: "Other Test"}); err == nil {
+		t.Error("NewTest with a different body should not match the cassette")
+	}
+}
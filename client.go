@@ -1,26 +1,60 @@
 package runscope
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 // BaseURL is the Runscope API URL
 const BaseURL = "https://api.runscope.com"
 
+// UserAgent is the default User-Agent header sent with every request
+const UserAgent = "go-runscope"
+
+// DefaultTimeout is the overall time limit for a single request when
+// no HTTPClient is supplied
+const DefaultTimeout = 60 * time.Second
+
 // Options used when creating a new client
 type Options struct {
 	BaseURL string
 	Token   string
+	// Retry enables retrying failed requests. Requests are only
+	// attempted once when it is nil.
+	Retry *RetryPolicy
+	// RateLimit enables client-side throttling of requests. Regardless
+	// of this setting, requests are held back after Runscope responds
+	// with a Retry-After header.
+	RateLimit *RateLimit
+	// HTTPClient is used to send requests instead of a client created
+	// by NewClient. Its transport is replaced when Transport is also set.
+	HTTPClient *http.Client
+	// Transport is used by the HTTP client instead of the default
+	// pooled transport, e.g. to configure a proxy or TLS certificates
+	Transport http.RoundTripper
+	// Timeout overrides DefaultTimeout. It is ignored when HTTPClient is set.
+	Timeout time.Duration
+	// UserAgent overrides the default User-Agent header
+	UserAgent string
+	// Logger enables logging of every request with LoggingMiddleware
+	Logger *slog.Logger
 }
 
 // Client is used when making requests to Runscope
 type Client struct {
 	*http.Client
-	token   string
-	baseURL string
+	token      string
+	baseURL    string
+	userAgent  string
+	retry      *RetryPolicy
+	limiter    *rateLimiter
+	middleware []Middleware
 }
 
 // Response represents the general response structure returned by Runscope
@@ -41,90 +75,199 @@ type Meta struct {
 	Status string `json:"status"`
 }
 
-func newHTTPClient() *http.Client {
+// NewTransport returns the default transport used by clients, which
+// keeps connections alive so that bulk operations reuse them
+func NewTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+func newHTTPClient(options Options) *http.Client {
+	if options.HTTPClient != nil {
+		if options.Transport == nil {
+			return options.HTTPClient
+		}
+		client := *options.HTTPClient
+		client.Transport = options.Transport
+		return &client
+	}
+
+	transport := options.Transport
+	if transport == nil {
+		transport = NewTransport()
+	}
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
 	return &http.Client{
-		Jar:       nil,
-		Transport: &http.Transport{DisableKeepAlives: true},
+		Transport: transport,
+		Timeout:   timeout,
 	}
 }
 
 // NewClient creates a new client for interacting with the Runscope API
 func NewClient(options Options) *Client {
-	client := newHTTPClient()
+	client := newHTTPClient(options)
 	if options.BaseURL == "" {
 		options.BaseURL = BaseURL
 	}
-	return &Client{
-		Client:  client,
-		token:   options.Token,
-		baseURL: options.BaseURL,
+	if options.UserAgent == "" {
+		options.UserAgent = UserAgent
+	}
+	c := &Client{
+		Client:    client,
+		token:     options.Token,
+		baseURL:   options.BaseURL,
+		userAgent: options.UserAgent,
+		retry:     options.Retry,
+		limiter:   newRateLimiter(options.RateLimit),
 	}
+	if options.Logger != nil {
+		c.Use(LoggingMiddleware(options.Logger))
+	}
+	return c
 }
 
 // Get performs a HTTP GET request against the Runscope API
 func (client *Client) Get(path string) ([]byte, error) {
+	return client.GetWithContext(context.Background(), path)
+}
+
+// GetWithContext performs a HTTP GET request against the Runscope API
+// bound to the supplied context
+func (client *Client) GetWithContext(ctx context.Context, path string) ([]byte, error) {
 	url := fmt.Sprintf("%s/%s", client.baseURL, path)
-	return client.doRequest("GET", url, nil)
+	return client.doRequest(ctx, "GET", url, nil)
 }
 
 // Post performs a HTTP POST request against the Rusncope API
 // with a supplied payload
 func (client *Client) Post(path string, data []byte) ([]byte, error) {
+	return client.PostWithContext(context.Background(), path, data)
+}
+
+// PostWithContext performs a HTTP POST request against the Runscope API
+// with a supplied payload, bound to the supplied context
+func (client *Client) PostWithContext(ctx context.Context, path string, data []byte) ([]byte, error) {
 	url := fmt.Sprintf("%s/%s", client.baseURL, path)
-	return client.doRequest("POST", url, data)
+	return client.doRequest(ctx, "POST", url, data)
 }
 
 // Put performs a HTTP PUT request against the Runscope API
 // with a supplied payload
 func (client *Client) Put(path string, data []byte) ([]byte, error) {
+	return client.PutWithContext(context.Background(), path, data)
+}
+
+// PutWithContext performs a HTTP PUT request against the Runscope API
+// with a supplied payload, bound to the supplied context
+func (client *Client) PutWithContext(ctx context.Context, path string, data []byte) ([]byte, error) {
 	url := fmt.Sprintf("%s/%s", client.baseURL, path)
-	return client.doRequest("PUT", url, data)
+	return client.doRequest(ctx, "PUT", url, data)
 }
 
 // Delete performs a HTTP DELETE request against the Runscope API
 func (client *Client) Delete(path string) error {
+	return client.DeleteWithContext(context.Background(), path)
+}
+
+// DeleteWithContext performs a HTTP DELETE request against the Runscope API
+// bound to the supplied context
+func (client *Client) DeleteWithContext(ctx context.Context, path string) error {
 	url := fmt.Sprintf("%s/%s", client.baseURL, path)
-	_, err := client.doRequest("DELETE", url, nil)
+	_, err := client.doRequest(ctx, "DELETE", url, nil)
 	return err
 }
 
-// checkStatusCode returns an error if a HTTP status code does not match 2xx
-func checkStatusCode(code int) error {
-	if 200 <= code && code < 300 {
-		return nil
+// checkStatusCode returns an APIError if a HTTP status code does not match
+// 2xx, or if a 2xx response envelope contains an error
+func checkStatusCode(req *APIRequest, res *APIResponse) error {
+	if 200 <= res.StatusCode && res.StatusCode < 300 {
+		var response Response
+		if err := json.Unmarshal(res.Body, &response); err != nil || response.Error.Message == "" {
+			return nil
+		}
+		return newAPIError(res.StatusCode, req.Method, req.Path, res.Body)
 	}
-	return fmt.Errorf("Request did not match 2xx: %d", code)
+	apiErr := newAPIError(res.StatusCode, req.Method, req.Path, res.Body)
+	apiErr.RetryAfter = parseRetryAfter(res.Header, time.Now())
+	return apiErr
 }
 
-func (client *Client) doRequest(method string, url string, data []byte) ([]byte, error) {
-	reqBody := bytes.NewReader(data)
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return nil, err
+func (client *Client) doRequest(ctx context.Context, method string, url string, data []byte) ([]byte, error) {
+	var (
+		body []byte
+		res  *APIResponse
+		err  error
+	)
+
+	attempts := client.retry.attempts()
+	for attempt := 1; ; attempt++ {
+		body, res, err = client.doAttempt(ctx, method, url, data)
+		if attempt >= attempts || !client.retry.shouldRetry(ctx, method, statusCode(res), err) {
+			return body, err
+		}
+		if sleepErr := sleep(ctx, client.retry.delay(attempt, err)); sleepErr != nil {
+			return body, err
+		}
 	}
-	setHeaders(req, client.token)
+}
 
-	res, err := client.Do(req)
+func (client *Client) doAttempt(ctx context.Context, method string, url string, data []byte) ([]byte, *APIResponse, error) {
+	if err := client.limiter.wait(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := newAPIRequest(method, url, data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer func() {
-		err = res.Body.Close()
-	}()
+	setHeaders(req.Header, client.token, client.userAgent)
 
-	body, err := ioutil.ReadAll(res.Body)
+	res, err := client.roundTrip()(ctx, req)
 	if err != nil {
-		return body, err
+		if res != nil {
+			return res.Body, res, err
+		}
+		return nil, nil, err
 	}
 
-	err = checkStatusCode(res.StatusCode)
-	return body, err
+	err = checkStatusCode(req, res)
+	if apiErr, ok := err.(*APIError); ok && apiErr.RetryAfter > 0 {
+		client.limiter.pause(apiErr.RetryAfter)
+	}
+	return res.Body, res, err
 }
 
-func setHeaders(req *http.Request, token string) {
-	req.Header = map[string][]string{
-		"Authorization": {"Bearer " + token},
-		"Accept":        {"application/json"},
-		"Content-Type":  {"application/json"},
+func newAPIRequest(method string, rawURL string, data []byte) (*APIRequest, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
 	}
+	return &APIRequest{
+		Method: method,
+		URL:    rawURL,
+		Path:   u.RequestURI(),
+		Header: http.Header{},
+		Body:   data,
+	}, nil
+}
+
+func setHeaders(header http.Header, token string, userAgent string) {
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("Accept", "application/json")
+	header.Set("Content-Type", "application/json")
+	header.Set("User-Agent", userAgent)
 }
@@ -1,6 +1,12 @@
 package runscope
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
 
 func TestNewClient(t *testing.T) {
 	client := NewClient(Options{})
@@ -8,3 +14,97 @@ func TestNewClient(t *testing.T) {
 		t.Fatalf("Client should not be nil")
 	}
 }
+
+func TestGetWithContextCancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetAccountWithContext(ctx)
+	if err == nil {
+		t.Fatal("GetAccountWithContext should return an error when the context expires")
+	}
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("Context error: %v, want %v", ctx.Err(), context.DeadlineExceeded)
+	}
+}
+
+type countingTransport struct {
+	requests int
+}
+
+func (transport *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientTransport(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleGet(t, "/account", http.StatusOK, `{"data": {}, "error": null, "meta": {"status": "success"}}`)
+
+	transport := &countingTransport{}
+	client := NewClient(Options{BaseURL: server.URL, Transport: transport})
+	if _, err := client.GetAccount(); err != nil {
+		t.Fatalf("GetAccount returned error: %v", err)
+	}
+	if transport.requests != 1 {
+		t.Errorf("Transport requests: %d, want %d", transport.requests, 1)
+	}
+	if client.Timeout != DefaultTimeout {
+		t.Errorf("Client timeout: %v, want %v", client.Timeout, DefaultTimeout)
+	}
+}
+
+func TestNewClientHTTPClient(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Second}
+	client := NewClient(Options{HTTPClient: httpClient})
+	if client.Client != httpClient {
+		t.Error("Client should use the supplied HTTPClient")
+	}
+
+	transport := &countingTransport{}
+	client = NewClient(Options{HTTPClient: httpClient, Transport: transport})
+	if client.Client == httpClient {
+		t.Error("Supplied HTTPClient should not be modified")
+	}
+	if client.Transport != transport || client.Timeout != time.Second {
+		t.Errorf("Client transport and timeout: %v %v, want %v %v", client.Transport, client.Timeout, transport, time.Second)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var userAgent string
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		fmt.Fprint(w, `{"data": {}, "error": null, "meta": {"status": "success"}}`)
+	})
+
+	if _, err := client.GetAccount(); err != nil {
+		t.Fatalf("GetAccount returned error: %v", err)
+	}
+	if userAgent != UserAgent {
+		t.Errorf("User-Agent: %v, want %v", userAgent, UserAgent)
+	}
+
+	client := NewClient(Options{BaseURL: server.URL, UserAgent: "deploy-gate/1.0"})
+	if _, err := client.GetAccount(); err != nil {
+		t.Fatalf("GetAccount returned error: %v", err)
+	}
+	if userAgent != "deploy-gate/1.0" {
+		t.Errorf("User-Agent: %v, want %v", userAgent, "deploy-gate/1.0")
+	}
+}
@@ -0,0 +1,74 @@
+// Command runscope-drift reports differences between a checked-in bucket
+// definition and the live state of the bucket. It exits with status 1
+// when drift is found and 2 on errors.
+//
+//	RUNSCOPE_TOKEN=... runscope-drift -config runscope.json -format json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	runscope "github.com/nextrevision/go-runscope"
+)
+
+func main() {
+	configPath := flag.String("config", "runscope.json", "path to the bucket definition, in JSON or YAML")
+	bucket := flag.String("bucket", "", "only check the bucket with this name")
+	format := flag.String("format", "text", "report format, text or json")
+	flag.Parse()
+
+	drifted, err := run(*configPath, *bucket, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if drifted {
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, bucket string, format string) (bool, error) {
+	if format != "text" && format != "json" {
+		return false, fmt.Errorf("unknown format %q", format)
+	}
+
+	config, err := runscope.LoadConfig(configPath)
+	if err != nil {
+		return false, err
+	}
+
+	if bucket != "" {
+		var selected []runscope.BucketConfig
+		for _, b := range config.Buckets {
+			if b.Name == bucket {
+				selected = append(selected, b)
+			}
+		}
+		if len(selected) == 0 {
+			return false, fmt.Errorf("bucket %q is not defined in %s", bucket, configPath)
+		}
+		config.Buckets = selected
+	}
+
+	client := runscope.NewClient(runscope.Options{
+		Token: os.Getenv("RUNSCOPE_TOKEN"),
+		Retry: runscope.DefaultRetryPolicy(),
+	})
+	report, err := client.DetectDrift(context.Background(), config)
+	if err != nil {
+		return false, err
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		_, err = fmt.Print(report)
+	}
+	return report.Drifted, err
+}
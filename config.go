@@ -0,0 +1,877 @@
+package runscope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Config is the desired state of a set of buckets, e.g. kept in git and
+// applied with ApplyConfig. Environments are referred to by name.
+type Config struct {
+	Buckets []BucketConfig `json:"buckets"`
+}
+
+// BucketConfig is the desired state of a bucket. An existing bucket is
+// matched by Key when set, otherwise by Name.
+type BucketConfig struct {
+	Name         string              `json:"name"`
+	Key          string              `json:"key,omitempty"`
+	TeamUUID     string              `json:"team_uuid,omitempty"`
+	Environments []EnvironmentConfig `json:"environments,omitempty"`
+	Tests        []TestConfig        `json:"tests,omitempty"`
+}
+
+// TestConfig is the desired state of a test, matched by Name.
+// DefaultEnvironment names one of the test's or the bucket's environments;
+// when empty, the test keeps its current default environment. An empty
+// Description leaves the live description as it is, since the API cannot
+// clear it.
+type TestConfig struct {
+	Name               string              `json:"name"`
+	Description        string              `json:"description,omitempty"`
+	DefaultEnvironment string              `json:"default_environment,omitempty"`
+	Steps              []Step              `json:"steps,omitempty"`
+	Environments       []EnvironmentConfig `json:"environments,omitempty"`
+	Schedules          []ScheduleConfig    `json:"schedules,omitempty"`
+}
+
+// EnvironmentConfig is the desired state of an environment, matched by
+// Name. Parent names the shared environment a test environment inherits
+// from.
+type EnvironmentConfig struct {
+	Environment
+	Parent string `json:"parent,omitempty"`
+}
+
+// ScheduleConfig is the desired state of a schedule, matched by its
+// environment and interval
+type ScheduleConfig struct {
+	Environment string `json:"environment"`
+	Interval    string `json:"interval"`
+	Note        string `json:"note,omitempty"`
+}
+
+// ChangeAction is the kind of a planned change
+type ChangeAction string
+
+// Planned change actions
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeDelete ChangeAction = "delete"
+)
+
+// Resource kinds of planned changes
+const (
+	ResourceBucket            = "bucket"
+	ResourceSharedEnvironment = "shared_environment"
+	ResourceTest              = "test"
+	ResourceTestEnvironment   = "test_environment"
+	ResourceStep              = "step"
+	ResourceSchedule          = "schedule"
+)
+
+// FieldChange is the change of a single field of a resource. Before is
+// nil for created resources and After is nil for deleted ones.
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// Change is a single planned change to a resource
+type Change struct {
+	Action   ChangeAction  `json:"action"`
+	Resource string        `json:"resource"`
+	Name     string        `json:"name"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+
+	apply func(ctx context.Context) error
+}
+
+// Plan is the ordered list of changes that bring live state in line with
+// a Config
+type Plan struct {
+	Changes []Change `json:"changes"`
+
+	deletes []Change
+}
+
+// LoadConfig reads a JSON or YAML desired state file
+func LoadConfig(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig parses and validates a desired state in JSON or YAML. YAML
+// is converted to JSON before it is decoded, so both accept the same
+// fields. Unknown fields are rejected so that typos are not silently
+// ignored.
+func ParseConfig(data []byte) (Config, error) {
+	var config Config
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		converted, err := yamlToJSON(data)
+		if err != nil {
+			return config, err
+		}
+		data = converted
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return config, err
+	}
+	return config, config.Validate()
+}
+
+// Validate checks that names are present and unique, that environment
+// references can be resolved and that steps are valid
+func (config Config) Validate() error {
+	buckets := map[string]bool{}
+	for _, bucket := range config.Buckets {
+		if bucket.Name == "" {
+			return fmt.Errorf("Bucket name is required")
+		}
+		if buckets[bucket.Name] {
+			return fmt.Errorf("Bucket %q is defined more than once", bucket.Name)
+		}
+		buckets[bucket.Name] = true
+
+		shared, err := environmentNames(bucket.Name, bucket.Environments)
+		if err != nil {
+			return err
+		}
+		for _, environment := range bucket.Environments {
+			if environment.Parent != "" {
+				return fmt.Errorf("Shared environment %q in %q cannot have a parent", environment.Name, bucket.Name)
+			}
+		}
+
+		tests := map[string]bool{}
+		for _, test := range bucket.Tests {
+			name := bucket.Name + "/" + test.Name
+			if test.Name == "" {
+				return fmt.Errorf("Test name is required in bucket %q", bucket.Name)
+			}
+			if tests[test.Name] {
+				return fmt.Errorf("Test %q is defined more than once", name)
+			}
+			tests[test.Name] = true
+
+			local, err := environmentNames(name, test.Environments)
+			if err != nil {
+				return err
+			}
+			for _, environment := range test.Environments {
+				if environment.Parent != "" && !shared[environment.Parent] {
+					return fmt.Errorf("Parent %q of environment %q in %q is not a shared environment", environment.Parent, environment.Name, name)
+				}
+			}
+
+			known := func(environment string) bool { return local[environment] || shared[environment] }
+			if test.DefaultEnvironment != "" && !known(test.DefaultEnvironment) {
+				return fmt.Errorf("Default environment %q of %q is not defined", test.DefaultEnvironment, name)
+			}
+			for _, schedule := range test.Schedules {
+				if !known(schedule.Environment) {
+					return fmt.Errorf("Environment %q of schedule in %q is not defined", schedule.Environment, name)
+				}
+				if schedule.Interval == "" {
+					return fmt.Errorf("Schedule interval is required in %q", name)
+				}
+			}
+
+			for i, step := range test.Steps {
+				if err := validateStep(step); err != nil {
+					return fmt.Errorf("Step %d of %q: %v", i, name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func environmentNames(scope string, environments []EnvironmentConfig) (map[string]bool, error) {
+	names := map[string]bool{}
+	for _, environment := range environments {
+		if environment.Name == "" {
+			return nil, fmt.Errorf("Environment name is required in %q", scope)
+		}
+		if names[environment.Name] {
+			return nil, fmt.Errorf("Environment %q is defined more than once in %q", environment.Name, scope)
+		}
+		names[environment.Name] = true
+	}
+	return names, nil
+}
+
+// PlanConfig compares a Config with the live state of its buckets and
+// returns the changes needed to apply it. Tests, environments, steps and
+// schedules missing from the config are deleted from managed buckets, but
+// buckets themselves are never deleted.
+func (client *Client) PlanConfig(ctx context.Context, config Config) (*Plan, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	buckets, err := client.ListBucketsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	for _, bucket := range config.Buckets {
+		if err := client.planBucket(ctx, plan, bucket, buckets); err != nil {
+			return nil, err
+		}
+	}
+
+	// delete in reverse so that dependent resources go first
+	for i := len(plan.deletes) - 1; i >= 0; i-- {
+		plan.Changes = append(plan.Changes, plan.deletes[i])
+	}
+	plan.deletes = nil
+	return plan, nil
+}
+
+// ApplyPlan executes the changes of a plan in order, stopping at the
+// first error
+func (client *Client) ApplyPlan(ctx context.Context, plan *Plan) error {
+	for _, change := range plan.Changes {
+		if err := change.apply(ctx); err != nil {
+			return fmt.Errorf("%s %s %q: %w", change.Action, change.Resource, change.Name, err)
+		}
+	}
+	return nil
+}
+
+// ApplyConfig plans a Config, writes the plan to out and applies it
+func (client *Client) ApplyConfig(ctx context.Context, config Config, out io.Writer) (*Plan, error) {
+	plan, err := client.PlanConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(out, plan.String()); err != nil {
+		return plan, err
+	}
+	return plan, client.ApplyPlan(ctx, plan)
+}
+
+// Empty reports whether the plan has no changes
+func (plan *Plan) Empty() bool {
+	return len(plan.Changes) == 0
+}
+
+// String returns a human-readable diff of the plan
+func (plan *Plan) String() string {
+	var out strings.Builder
+	counts := map[ChangeAction]int{}
+	symbols := map[ChangeAction]string{ChangeCreate: "+", ChangeUpdate: "~", ChangeDelete: "-"}
+
+	for _, change := range plan.Changes {
+		counts[change.Action]++
+		fmt.Fprintf(&out, "%s %s %q\n", symbols[change.Action], change.Resource, change.Name)
+		for _, field := range change.Fields {
+			switch change.Action {
+			case ChangeUpdate:
+				fmt.Fprintf(&out, "    %s: %s => %s\n", field.Field, display(field.Before), display(field.After))
+			case ChangeDelete:
+				fmt.Fprintf(&out, "    %s: %s\n", field.Field, display(field.Before))
+			default:
+				fmt.Fprintf(&out, "    %s: %s\n", field.Field, display(field.After))
+			}
+		}
+	}
+
+	if plan.Empty() {
+		out.WriteString("No changes.\n")
+	} else {
+		fmt.Fprintf(&out, "Plan: %d to create, %d to update, %d to delete.\n",
+			counts[ChangeCreate], counts[ChangeUpdate], counts[ChangeDelete])
+	}
+	return out.String()
+}
+
+func (plan *Plan) add(change Change) {
+	if change.Action == ChangeDelete {
+		plan.deletes = append(plan.deletes, change)
+	} else {
+		plan.Changes = append(plan.Changes, change)
+	}
+}
+
+// environmentRefs maps environment names to their IDs, which are filled
+// in when planned environments are created
+type environmentRefs map[string]*string
+
+func (refs environmentRefs) lookup(name string, fallback environmentRefs) *string {
+	if id, ok := refs[name]; ok {
+		return id
+	}
+	return fallback[name]
+}
+
+func (client *Client) planBucket(ctx context.Context, plan *Plan, desired BucketConfig, buckets []Bucket) error {
+	var live *Bucket
+	for i := range buckets {
+		if (desired.Key != "" && buckets[i].Key == desired.Key) || (desired.Key == "" && buckets[i].Name == desired.Name) {
+			live = &buckets[i]
+			break
+		}
+	}
+
+	key := new(string)
+	var sharedEnvironments []Environment
+	var tests []Test
+	if live == nil {
+		request := NewBucketRequest{Name: desired.Name, TeamUUID: desired.TeamUUID}
+		plan.add(Change{
+			Action:   ChangeCreate,
+			Resource: ResourceBucket,
+			Name:     desired.Name,
+			Fields:   diffFields(nil, canonical(request)),
+			apply: func(ctx context.Context) error {
+				bucket, err := client.NewBucketWithContext(ctx, &request)
+				*key = bucket.Key
+				return err
+			},
+		})
+	} else {
+		*key = live.Key
+		var err error
+		if sharedEnvironments, err = client.ListSharedEnvironmentsWithContext(ctx, live.Key); err != nil {
+			return err
+		}
+		if tests, err = client.ListAllTestsWithContext(ctx, live.Key); err != nil {
+			return err
+		}
+	}
+
+	// match tests first, so that the default environments of kept tests
+	// are known before planning shared environments
+	matched := map[string]bool{}
+	liveTests := make([]*Test, len(desired.Tests))
+	keep := map[string]bool{}
+	for i, test := range desired.Tests {
+		for _, candidate := range tests {
+			if candidate.Name == test.Name && !matched[candidate.ID] {
+				details, err := client.GetTestWithContext(ctx, *key, candidate.ID)
+				if err != nil {
+					return err
+				}
+				liveTests[i] = &details
+				matched[candidate.ID] = true
+				if test.DefaultEnvironment == "" {
+					keep[details.DefaultEnvironmentID] = true
+				}
+				break
+			}
+		}
+	}
+
+	names := environmentIDNames(sharedEnvironments)
+	shared := environmentRefs{}
+	planEnvironments(plan, ResourceSharedEnvironment, desired.Name, desired.Environments, sharedEnvironments, names, shared, nil, keep,
+		func(ctx context.Context, environment Environment) (Environment, error) {
+			return client.NewSharedEnvironmentWithContext(ctx, *key, environment)
+		},
+		func(ctx context.Context, id string, environment Environment) (Environment, error) {
+			return client.UpdateSharedEnvironmentWithContext(ctx, *key, id, environment)
+		},
+		func(ctx context.Context, id string) error {
+			return client.DeleteEnvironmentWithContext(ctx, *key, id)
+		})
+
+	for i, test := range desired.Tests {
+		if err := client.planTest(ctx, plan, key, desired.Name, test, liveTests[i], shared, names); err != nil {
+			return err
+		}
+	}
+
+	for _, test := range tests {
+		if matched[test.ID] {
+			continue
+		}
+		id := test.ID
+		plan.add(Change{
+			Action:   ChangeDelete,
+			Resource: ResourceTest,
+			Name:     desired.Name + "/" + test.Name,
+			Fields:   diffFields(canonical(NewTestRequest{Name: test.Name, Description: test.Description}), nil),
+			apply: func(ctx context.Context) error {
+				return client.DeleteTestWithContext(ctx, *key, id)
+			},
+		})
+	}
+	return nil
+}
+
+func (client *Client) planTest(ctx context.Context, plan *Plan, key *string, bucketName string, desired TestConfig, live *Test, shared environmentRefs, names map[string]string) error {
+	name := bucketName + "/" + desired.Name
+	testID := new(string)
+	// environments Runscope creates along with a new test, adopted by
+	// name when the config defines them
+	created := &[]Environment{}
+
+	after := canonical(NewTestRequest{Name: desired.Name, Description: desired.Description})
+	if live == nil {
+		request := NewTestRequest{Name: desired.Name, Description: desired.Description}
+		plan.add(Change{
+			Action:   ChangeCreate,
+			Resource: ResourceTest,
+			Name:     name,
+			Fields:   diffFields(nil, after),
+			apply: func(ctx context.Context) error {
+				test, err := client.NewTestWithContext(ctx, *key, request)
+				*testID = test.ID
+				*created = test.Environments
+				return err
+			},
+		})
+		live = &Test{}
+	} else {
+		*testID = live.ID
+		before := canonical(NewTestRequest{Name: live.Name, Description: live.Description})
+		if desired.Description == "" {
+			// updates cannot clear a description, so leave it alone
+			delete(before, "description")
+		}
+		if fields := diffFields(before, after); len(fields) > 0 {
+			request := UpdateTestRequest{Name: desired.Name, Description: desired.Description}
+			plan.add(Change{
+				Action:   ChangeUpdate,
+				Resource: ResourceTest,
+				Name:     name,
+				Fields:   fields,
+				apply: func(ctx context.Context) error {
+					_, err := client.UpdateTestWithContext(ctx, *key, *testID, request)
+					return err
+				},
+			})
+		}
+	}
+
+	testNames := environmentIDNames(live.Environments)
+	for id, environment := range names {
+		testNames[id] = environment
+	}
+	local := environmentRefs{}
+	// the default environment cannot be deleted, so it is kept unless the
+	// config moves the default elsewhere. This includes the environment
+	// Runscope creates with every test.
+	keep := map[string]bool{}
+	if desired.DefaultEnvironment == "" {
+		keep[live.DefaultEnvironmentID] = true
+	}
+	planEnvironments(plan, ResourceTestEnvironment, name, desired.Environments, live.Environments, testNames, local, shared, keep,
+		func(ctx context.Context, environment Environment) (Environment, error) {
+			for _, existing := range *created {
+				if existing.Name == environment.Name {
+					return client.UpdateTestEnvironmentWithContext(ctx, *key, *testID, existing.ID, environment)
+				}
+			}
+			return client.NewTestEnvironmentWithContext(ctx, *key, *testID, environment)
+		},
+		func(ctx context.Context, id string, environment Environment) (Environment, error) {
+			return client.UpdateTestEnvironmentWithContext(ctx, *key, *testID, id, environment)
+		},
+		func(ctx context.Context, id string) error {
+			return client.DeleteEnvironmentWithContext(ctx, *key, id)
+		})
+
+	planSteps(plan, name, desired.Steps, live.Steps,
+		func(ctx context.Context, step Step) error {
+			_, err := client.NewStepWithContext(ctx, *key, *testID, step)
+			return err
+		},
+		func(ctx context.Context, id string, step Step) error {
+			_, err := client.UpdateStepWithContext(ctx, *key, *testID, id, step)
+			return err
+		},
+		func(ctx context.Context, id string) error {
+			return client.DeleteStepWithContext(ctx, *key, *testID, id)
+		})
+
+	if desired.DefaultEnvironment != "" && testNames[live.DefaultEnvironmentID] != desired.DefaultEnvironment {
+		environmentID := local.lookup(desired.DefaultEnvironment, shared)
+		plan.add(Change{
+			Action:   ChangeUpdate,
+			Resource: ResourceTest,
+			Name:     name,
+			Fields:   []FieldChange{{Field: "default_environment", Before: nilIfEmpty(testNames[live.DefaultEnvironmentID]), After: desired.DefaultEnvironment}},
+			apply: func(ctx context.Context) error {
+				_, err := client.UpdateTestWithContext(ctx, *key, *testID, UpdateTestRequest{DefaultEnvironmentID: *environmentID})
+				return err
+			},
+		})
+	}
+
+	var schedules []Schedule
+	if live.ID != "" {
+		var err error
+		if schedules, err = client.ListSchedulesWithContext(ctx, *key, live.ID); err != nil {
+			return err
+		}
+	}
+	planSchedules(plan, name, desired.Schedules, schedules, testNames, local, shared,
+		func(ctx context.Context, schedule Schedule) error {
+			_, err := client.NewScheduleWithContext(ctx, *key, *testID, schedule)
+			return err
+		},
+		func(ctx context.Context, id string, schedule Schedule) error {
+			_, err := client.UpdateScheduleWithContext(ctx, *key, *testID, id, schedule)
+			return err
+		},
+		func(ctx context.Context, id string) error {
+			return client.DeleteScheduleWithContext(ctx, *key, *testID, id)
+		})
+	return nil
+}
+
+func planEnvironments(
+	plan *Plan,
+	resource string,
+	scope string,
+	desired []EnvironmentConfig,
+	live []Environment,
+	names map[string]string,
+	refs environmentRefs,
+	parents environmentRefs,
+	keep map[string]bool,
+	create func(context.Context, Environment) (Environment, error),
+	update func(context.Context, string, Environment) (Environment, error),
+	remove func(context.Context, string) error,
+) {
+	matched := map[string]bool{}
+	for _, environment := range desired {
+		environment := environment
+		id := new(string)
+		refs[environment.Name] = id
+
+		var existing *Environment
+		for i := range live {
+			if live[i].Name == environment.Name && !matched[live[i].ID] {
+				existing = &live[i]
+				matched[existing.ID] = true
+				break
+			}
+		}
+
+		after := environmentView(environment.Environment, environment.Parent)
+		request := func() Environment {
+			e := environment.Environment
+			e.ID = ""
+			e.ParentEnvironmentID = ""
+			if environment.Parent != "" {
+				e.ParentEnvironmentID = *parents[environment.Parent]
+			}
+			return e
+		}
+
+		name := scope + "/" + environment.Name
+		if existing == nil {
+			plan.add(Change{
+				Action:   ChangeCreate,
+				Resource: resource,
+				Name:     name,
+				Fields:   diffFields(nil, after),
+				apply: func(ctx context.Context) error {
+					created, err := create(ctx, request())
+					*id = created.ID
+					return err
+				},
+			})
+			continue
+		}
+
+		*id = existing.ID
+		before := environmentView(*existing, names[existing.ParentEnvironmentID])
+		if fields := diffFields(before, after); len(fields) > 0 {
+			plan.add(Change{
+				Action:   ChangeUpdate,
+				Resource: resource,
+				Name:     name,
+				Fields:   fields,
+				apply: func(ctx context.Context) error {
+					_, err := update(ctx, *id, request())
+					return err
+				},
+			})
+		}
+	}
+
+	for _, environment := range live {
+		if matched[environment.ID] || keep[environment.ID] {
+			continue
+		}
+		id := environment.ID
+		plan.add(Change{
+			Action:   ChangeDelete,
+			Resource: resource,
+			Name:     scope + "/" + environment.Name,
+			Fields:   diffFields(environmentView(environment, names[environment.ParentEnvironmentID]), nil),
+			apply: func(ctx context.Context) error {
+				return remove(ctx, id)
+			},
+		})
+	}
+}
+
+// planSteps compares steps by position, updating steps in place
+func planSteps(
+	plan *Plan,
+	scope string,
+	desired []Step,
+	live []Step,
+	create func(context.Context, Step) error,
+	update func(context.Context, string, Step) error,
+	remove func(context.Context, string) error,
+) {
+	for i, step := range desired {
+		step := stepView(step)
+		name := fmt.Sprintf("%s/steps[%d]", scope, i)
+		after := canonical(step)
+
+		if i >= len(live) {
+			plan.add(Change{
+				Action:   ChangeCreate,
+				Resource: ResourceStep,
+				Name:     name,
+				Fields:   diffFields(nil, after),
+				apply: func(ctx context.Context) error {
+					return create(ctx, step)
+				},
+			})
+			continue
+		}
+
+		id := live[i].ID
+		if fields := diffFields(canonical(stepView(live[i])), after); len(fields) > 0 {
+			plan.add(Change{
+				Action:   ChangeUpdate,
+				Resource: ResourceStep,
+				Name:     name,
+				Fields:   fields,
+				apply: func(ctx context.Context) error {
+					return update(ctx, id, step)
+				},
+			})
+		}
+	}
+
+	for i := len(desired); i < len(live); i++ {
+		id := live[i].ID
+		plan.add(Change{
+			Action:   ChangeDelete,
+			Resource: ResourceStep,
+			Name:     fmt.Sprintf("%s/steps[%d]", scope, i),
+			Fields:   diffFields(canonical(stepView(live[i])), nil),
+			apply: func(ctx context.Context) error {
+				return remove(ctx, id)
+			},
+		})
+	}
+}
+
+func planSchedules(
+	plan *Plan,
+	scope string,
+	desired []ScheduleConfig,
+	live []Schedule,
+	names map[string]string,
+	local environmentRefs,
+	shared environmentRefs,
+	create func(context.Context, Schedule) error,
+	update func(context.Context, string, Schedule) error,
+	remove func(context.Context, string) error,
+) {
+	matched := map[string]bool{}
+	for _, schedule := range desired {
+		schedule := schedule
+		name := fmt.Sprintf("%s/%s every %s", scope, schedule.Environment, schedule.Interval)
+		environmentID := local.lookup(schedule.Environment, shared)
+		request := func() Schedule {
+			return Schedule{Note: schedule.Note, Interval: schedule.Interval, EnvironmentID: *environmentID}
+		}
+
+		var existing *Schedule
+		for i := range live {
+			if names[live[i].EnvironmentID] == schedule.Environment && live[i].Interval == schedule.Interval && !matched[live[i].ID] {
+				existing = &live[i]
+				matched[existing.ID] = true
+				break
+			}
+		}
+
+		if existing == nil {
+			plan.add(Change{
+				Action:   ChangeCreate,
+				Resource: ResourceSchedule,
+				Name:     name,
+				Fields:   diffFields(nil, canonical(schedule)),
+				apply: func(ctx context.Context) error {
+					return create(ctx, request())
+				},
+			})
+			continue
+		}
+
+		id := existing.ID
+		if existing.Note != schedule.Note {
+			plan.add(Change{
+				Action:   ChangeUpdate,
+				Resource: ResourceSchedule,
+				Name:     name,
+				Fields:   []FieldChange{{Field: "note", Before: nilIfEmpty(existing.Note), After: nilIfEmpty(schedule.Note)}},
+				apply: func(ctx context.Context) error {
+					return update(ctx, id, request())
+				},
+			})
+		}
+	}
+
+	for _, schedule := range live {
+		if matched[schedule.ID] {
+			continue
+		}
+		id := schedule.ID
+		view := ScheduleConfig{Environment: names[schedule.EnvironmentID], Interval: schedule.Interval, Note: schedule.Note}
+		plan.add(Change{
+			Action:   ChangeDelete,
+			Resource: ResourceSchedule,
+			Name:     fmt.Sprintf("%s/%s every %s", scope, view.Environment, view.Interval),
+			Fields:   diffFields(canonical(view), nil),
+			apply: func(ctx context.Context) error {
+				return remove(ctx, id)
+			},
+		})
+	}
+}
+
+func environmentIDNames(environments []Environment) map[string]string {
+	names := map[string]string{}
+	for _, environment := range environments {
+		names[environment.ID] = environment.Name
+	}
+	return names
+}
+
+// environmentView returns the comparable fields of an environment, with
+// its parent referred to by name
+func environmentView(environment Environment, parent string) map[string]interface{} {
+	environment.ID = ""
+	environment.TestID = ""
+	environment.ParentEnvironmentID = parent
+	view := canonical(environment)
+	if parent != "" {
+		delete(view, "parent_environment_id")
+		view["parent"] = parent
+	}
+	return view
+}
+
+// stepView clears the IDs of a step and its nested steps
+func stepView(step Step) Step {
+	step.ID = ""
+	if step.StepType == "" {
+		step.StepType = StepTypeRequest
+	}
+	if step.Steps != nil {
+		nested := make([]Step, len(step.Steps))
+		for i, s := range step.Steps {
+			nested[i] = stepView(s)
+		}
+		step.Steps = nested
+	}
+	return step
+}
+
+// canonical returns the JSON form of a value as a map, without empty
+// fields, so that values can be compared regardless of how they were
+// decoded
+func canonical(value interface{}) map[string]interface{} {
+	data, _ := json.Marshal(value)
+	var view map[string]interface{}
+	json.Unmarshal(data, &view)
+	pruned, _ := prune(view).(map[string]interface{})
+	if pruned == nil {
+		pruned = map[string]interface{}{}
+	}
+	return pruned
+}
+
+// prune removes nulls, empty strings and empty collections
+func prune(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if item = prune(item); item == nil {
+				delete(v, key)
+			} else {
+				v[key] = item
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, item := range v {
+			v[i] = prune(item)
+		}
+	case string:
+		if v == "" {
+			return nil
+		}
+	}
+	return value
+}
+
+// diffFields returns the fields that differ between two canonical views,
+// sorted by name
+func diffFields(before, after map[string]interface{}) []FieldChange {
+	keys := map[string]bool{}
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	fields := []FieldChange{}
+	for key := range keys {
+		if !reflect.DeepEqual(before[key], after[key]) {
+			fields = append(fields, FieldChange{Field: key, Before: before[key], After: after[key]})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return fields
+}
+
+func nilIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func display(value interface{}) string {
+	if value == nil {
+		return "(none)"
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,163 @@
+package runscope_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	runscope "github.com/nextrevision/go-runscope"
+	"github.com/nextrevision/go-runscope/runscopetest"
+)
+
+const desiredConfig = `{
+  "buckets": [{
+    "name": "Mobile Apps",
+    "environments": [{
+      "name": "Production",
+      "regions": ["us1"],
+      "initial_variables": {"baseUrl": "https://api.example.com"}
+    }],
+    "tests": [{
+      "name": "Login",
+      "description": "Logs in",
+      "default_environment": "Canary",
+      "steps": [
+        {"step_type": "request", "method": "GET", "url": "{{baseUrl}}/health",
+         "assertions": [{"source": "response_status", "comparison": "equal_number", "value": 200}]},
+        {"step_type": "pause", "duration": 1}
+      ],
+      "environments": [
+        {"name": "Test Settings", "regions": ["us1"], "verify_ssl": true},
+        {"name": "Canary", "parent": "Production", "initial_variables": {"baseUrl": "https://canary.example.com"}}
+      ],
+      "schedules": [{"environment": "Canary", "interval": "1h", "note": "hourly"}]
+    }]
+  }]
+}`
+
+func TestApplyConfig(t *testing.T) {
+	server := runscopetest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	config, err := runscope.ParseConfig([]byte(desiredConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig returned error: %v", err)
+	}
+
+	var out bytes.Buffer
+	plan, err := client.ApplyConfig(ctx, config, &out)
+	if err != nil {
+		t.Fatalf("ApplyConfig returned error: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), `+ bucket "Mobile Apps"`) || !strings.Contains(out.String(), "Plan: 8 to create, 1 to update, 0 to delete.") {
+		t.Errorf("unexpected plan output:\n%s", out.String())
+	}
+	if len(plan.Changes) != 9 {
+		t.Errorf("expected 9 changes, got %d", len(plan.Changes))
+	}
+
+	plan, err = client.PlanConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("PlanConfig returned error: %v", err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("expected no changes after apply, got:\n%s", plan)
+	}
+
+	buckets, _ := client.ListBuckets()
+	tests, _ := client.ListAllTests(buckets[0].Key)
+	test, _ := client.GetTest(buckets[0].Key, tests[0].ID)
+	if len(test.Steps) != 2 || len(test.Environments) != 2 {
+		t.Errorf("expected 2 steps and 2 environments, got %+v", test)
+	}
+	for _, environment := range test.Environments {
+		if environment.Name == "Canary" && test.DefaultEnvironmentID != environment.ID {
+			t.Errorf("expected Canary to be the default environment")
+		}
+	}
+}
+
+func TestPlanConfigChanges(t *testing.T) {
+	server := runscopetest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	config, _ := runscope.ParseConfig([]byte(desiredConfig))
+	if _, err := client.ApplyConfig(ctx, config, &bytes.Buffer{}); err != nil {
+		t.Fatalf("ApplyConfig returned error: %v", err)
+	}
+
+	test := &config.Buckets[0].Tests[0]
+	test.Steps[0].URL = "{{baseUrl}}/status"
+	test.Steps = test.Steps[:1]
+	test.Schedules[0].Note = "every hour"
+	config.Buckets[0].Tests = append(config.Buckets[0].Tests, runscope.TestConfig{Name: "Signup"})
+
+	plan, err := client.PlanConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("PlanConfig returned error: %v", err)
+	}
+
+	want := []string{
+		`~ step "Mobile Apps/Login/steps[0]"`,
+		`    url: "{{baseUrl}}/health" => "{{baseUrl}}/status"`,
+		`~ schedule "Mobile Apps/Login/Canary every 1h"`,
+		`+ test "Mobile Apps/Signup"`,
+		`- step "Mobile Apps/Login/steps[1]"`,
+		"Plan: 1 to create, 2 to update, 1 to delete.",
+	}
+	for _, line := range want {
+		if !strings.Contains(plan.String(), line) {
+			t.Errorf("expected plan to contain %q, got:\n%s", line, plan)
+		}
+	}
+
+	if err := client.ApplyPlan(ctx, plan); err != nil {
+		t.Fatalf("ApplyPlan returned error: %v", err)
+	}
+	if plan, _ := client.PlanConfig(ctx, config); !plan.Empty() {
+		t.Errorf("expected no changes after apply, got:\n%s", plan)
+	}
+}
+
+func TestApplyConfigConverges(t *testing.T) {
+	server := runscopetest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	configs := []string{
+		`{"buckets": [{"name": "B", "tests": [{"name": "T", "description": "d"}]}]}`,
+		// the description cannot be cleared, so it is left alone
+		`{"buckets": [{"name": "B", "tests": [{"name": "T"}]}]}`,
+		// the default environment is kept alongside listed environments
+		`{"buckets": [{"name": "B", "tests": [{"name": "T", "environments": [{"name": "Staging"}]}]}]}`,
+	}
+	for _, data := range configs {
+		config, err := runscope.ParseConfig([]byte(data))
+		if err != nil {
+			t.Fatalf("ParseConfig returned error: %v", err)
+		}
+		if _, err := client.ApplyConfig(ctx, config, &bytes.Buffer{}); err != nil {
+			t.Fatalf("ApplyConfig(%s) returned error: %v", data, err)
+		}
+		plan, err := client.PlanConfig(ctx, config)
+		if err != nil {
+			t.Fatalf("PlanConfig returned error: %v", err)
+		}
+		if !plan.Empty() {
+			t.Errorf("expected no changes after applying %s, got:\n%s", data, plan)
+		}
+	}
+
+	buckets, _ := client.ListBuckets()
+	tests, _ := client.ListAllTests(buckets[0].Key)
+	test, _ := client.GetTest(buckets[0].Key, tests[0].ID)
+	if len(test.Environments) != 2 || test.Environments[0].ID != test.DefaultEnvironmentID {
+		t.Errorf("expected the default environment to be kept, got %+v", test.Environments)
+	}
+}
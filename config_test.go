@@ -0,0 +1,105 @@
+package runscope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfigInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"unknown field":        `{"buckets": [{"name": "A", "tset": []}]}`,
+		"missing bucket name":  `{"buckets": [{}]}`,
+		"duplicate bucket":     `{"buckets": [{"name": "A"}, {"name": "A"}]}`,
+		"duplicate test":       `{"buckets": [{"name": "A", "tests": [{"name": "T"}, {"name": "T"}]}]}`,
+		"unknown default":      `{"buckets": [{"name": "A", "tests": [{"name": "T", "default_environment": "Prod"}]}]}`,
+		"unknown parent":       `{"buckets": [{"name": "A", "tests": [{"name": "T", "environments": [{"name": "E", "parent": "Prod"}]}]}]}`,
+		"shared parent":        `{"buckets": [{"name": "A", "environments": [{"name": "E", "parent": "F"}, {"name": "F"}]}]}`,
+		"schedule environment": `{"buckets": [{"name": "A", "tests": [{"name": "T", "schedules": [{"environment": "E", "interval": "1h"}]}]}]}`,
+		"invalid step":         `{"buckets": [{"name": "A", "tests": [{"name": "T", "steps": [{"step_type": "request", "assertions": [{"source": "response_body"}]}]}]}]}`,
+	}
+
+	for name, data := range invalid {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("%s: expected ParseConfig to return an error", name)
+		}
+	}
+}
+
+func TestParseConfigYAML(t *testing.T) {
+	yamlConfig := `
+buckets:
+  - name: Mobile Apps
+    environments:
+      - name: Production
+        regions: [us1]
+        initial_variables:
+          baseUrl: https://api.example.com
+    tests:
+      - name: Login
+        default_environment: Production
+        steps:
+          - step_type: request
+            method: GET
+            url: "{{baseUrl}}/health"
+            assertions:
+              - {source: response_status, comparison: equal_number, value: 200}
+            scripts:
+              - |
+                log("done");
+        schedules:
+          - environment: Production
+            interval: 1h
+`
+	jsonConfig := `{"buckets": [{
+  "name": "Mobile Apps",
+  "environments": [{"name": "Production", "regions": ["us1"], "initial_variables": {"baseUrl": "https://api.example.com"}}],
+  "tests": [{
+    "name": "Login",
+    "default_environment": "Production",
+    "steps": [{"step_type": "request", "method": "GET", "url": "{{baseUrl}}/health",
+      "assertions": [{"source": "response_status", "comparison": "equal_number", "value": 200}],
+      "scripts": ["log(\"done\");\n"]}],
+    "schedules": [{"environment": "Production", "interval": "1h"}]
+  }]
+}]}`
+
+	got, err := ParseConfig([]byte(yamlConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig returned error for YAML: %v", err)
+	}
+	want, err := ParseConfig([]byte(jsonConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig returned error for JSON: %v", err)
+	}
+	testResponseData(t, got, want)
+
+	if _, err := ParseConfig([]byte("buckets:\n  - name: A\n    tset: []\n")); err == nil {
+		t.Error("expected ParseConfig to reject unknown fields in YAML")
+	}
+}
+
+func TestPlanString(t *testing.T) {
+	plan := &Plan{Changes: []Change{
+		{Action: ChangeCreate, Resource: ResourceTest, Name: "A/T", Fields: []FieldChange{{Field: "name", After: "T"}}},
+		{Action: ChangeUpdate, Resource: ResourceSchedule, Name: "A/T/E every 1h", Fields: []FieldChange{{Field: "note", After: "hourly"}}},
+		{Action: ChangeDelete, Resource: ResourceStep, Name: "A/T/steps[1]", Fields: []FieldChange{{Field: "duration", Before: 5}}},
+	}}
+
+	want := strings.Join([]string{
+		`+ test "A/T"`,
+		`    name: "T"`,
+		`~ schedule "A/T/E every 1h"`,
+		`    note: (none) => "hourly"`,
+		`- step "A/T/steps[1]"`,
+		`    duration: 5`,
+		"Plan: 1 to create, 1 to update, 1 to delete.",
+		"",
+	}, "\n")
+	if got := plan.String(); got != want {
+		t.Errorf("Plan.String returned:\n%s\nexpected:\n%s", got, want)
+	}
+
+	if got := (&Plan{}).String(); got != "No changes.\n" {
+		t.Errorf("expected empty plan to have no changes, got %q", got)
+	}
+}
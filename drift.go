@@ -0,0 +1,86 @@
+package runscope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DriftType is the kind of difference between a definition and live state
+type DriftType string
+
+// Drift types, from the point of view of the live bucket
+const (
+	// DriftAdded is a resource that is live but not in the definition
+	DriftAdded DriftType = "added"
+	// DriftRemoved is a resource in the definition that is not live
+	DriftRemoved DriftType = "removed"
+	// DriftModified is a resource whose live fields differ from the
+	// definition
+	DriftModified DriftType = "modified"
+)
+
+// Drift is a single resource that differs from its definition. The
+// Before value of each field is the definition and After is live.
+type Drift struct {
+	Type     DriftType     `json:"type"`
+	Resource string        `json:"resource"`
+	Name     string        `json:"name"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+}
+
+// DriftReport lists the differences between a definition and the live
+// state of its buckets
+type DriftReport struct {
+	Drifted bool    `json:"drifted"`
+	Drift   []Drift `json:"drift"`
+}
+
+// DetectDrift compares a Config with the live state of its buckets
+// without changing anything
+func (client *Client) DetectDrift(ctx context.Context, config Config) (*DriftReport, error) {
+	plan, err := client.PlanConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{Drift: []Drift{}}
+	for _, change := range plan.Changes {
+		drift := Drift{Resource: change.Resource, Name: change.Name}
+		switch change.Action {
+		case ChangeCreate:
+			drift.Type = DriftRemoved
+		case ChangeDelete:
+			drift.Type = DriftAdded
+		case ChangeUpdate:
+			drift.Type = DriftModified
+		}
+		// a plan goes from live to the definition, drift the other way
+		for _, field := range change.Fields {
+			drift.Fields = append(drift.Fields, FieldChange{Field: field.Field, Before: field.After, After: field.Before})
+		}
+		report.Drift = append(report.Drift, drift)
+	}
+	report.Drifted = len(report.Drift) > 0
+	return report, nil
+}
+
+// String returns a human-readable summary of the report
+func (report *DriftReport) String() string {
+	if !report.Drifted {
+		return "No drift.\n"
+	}
+
+	var out strings.Builder
+	counts := map[DriftType]int{}
+	for _, drift := range report.Drift {
+		counts[drift.Type]++
+		fmt.Fprintf(&out, "%s %s %q\n", drift.Type, drift.Resource, drift.Name)
+		for _, field := range drift.Fields {
+			fmt.Fprintf(&out, "    %s: %s => %s\n", field.Field, display(field.Before), display(field.After))
+		}
+	}
+	fmt.Fprintf(&out, "Drift: %d added, %d removed, %d modified.\n",
+		counts[DriftAdded], counts[DriftRemoved], counts[DriftModified])
+	return out.String()
+}
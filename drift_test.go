@@ -0,0 +1,93 @@
+package runscope_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	runscope "github.com/nextrevision/go-runscope"
+	"github.com/nextrevision/go-runscope/runscopetest"
+)
+
+func TestDetectDrift(t *testing.T) {
+	server := runscopetest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	config, _ := runscope.ParseConfig([]byte(desiredConfig))
+	if _, err := client.ApplyConfig(ctx, config, &bytes.Buffer{}); err != nil {
+		t.Fatalf("ApplyConfig returned error: %v", err)
+	}
+
+	report, err := client.DetectDrift(ctx, config)
+	if err != nil {
+		t.Fatalf("DetectDrift returned error: %v", err)
+	}
+	if report.Drifted || report.String() != "No drift.\n" {
+		t.Fatalf("expected no drift after apply, got:\n%s", report)
+	}
+
+	buckets, _ := client.ListBuckets()
+	key := buckets[0].Key
+	shared, _ := client.ListSharedEnvironments(key)
+	production := shared[0]
+	production.Regions = []string{"eu1"}
+	if _, err := client.UpdateSharedEnvironment(key, production.ID, production); err != nil {
+		t.Fatalf("UpdateSharedEnvironment returned error: %v", err)
+	}
+	client.NewTest(key, runscope.NewTestRequest{Name: "Hotfix"})
+	tests, _ := client.ListAllTests(key)
+	schedules, _ := client.ListSchedules(key, tests[0].ID)
+	client.DeleteSchedule(key, tests[0].ID, schedules[0].ID)
+
+	report, err = client.DetectDrift(ctx, config)
+	if err != nil {
+		t.Fatalf("DetectDrift returned error: %v", err)
+	}
+
+	want := []string{
+		`modified shared_environment "Mobile Apps/Production"`,
+		`    regions: ["us1"] => ["eu1"]`,
+		`removed schedule "Mobile Apps/Login/Canary every 1h"`,
+		`    interval: "1h" => (none)`,
+		`added test "Mobile Apps/Hotfix"`,
+		`    name: (none) => "Hotfix"`,
+		"Drift: 1 added, 1 removed, 1 modified.",
+	}
+	for _, line := range want {
+		if !strings.Contains(report.String(), line) {
+			t.Errorf("expected report to contain %q, got:\n%s", line, report)
+		}
+	}
+
+	data, _ := json.Marshal(report)
+	var decoded runscope.DriftReport
+	if err := json.Unmarshal(data, &decoded); err != nil || !decoded.Drifted || len(decoded.Drift) != 3 {
+		t.Errorf("unexpected JSON report %s", data)
+	}
+}
+
+func TestDetectDriftAfterApply(t *testing.T) {
+	server := runscopetest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	// tests without environments keep the one Runscope creates for them
+	config, _ := runscope.ParseConfig([]byte(`{"buckets": [{"name": "B", "tests": [{"name": "T"}, {"name": "U", "description": "d"}]}]}`))
+	if _, err := client.ApplyConfig(ctx, config, &bytes.Buffer{}); err != nil {
+		t.Fatalf("ApplyConfig returned error: %v", err)
+	}
+	config.Buckets[0].Tests[1].Description = ""
+
+	report, err := client.DetectDrift(ctx, config)
+	if err != nil {
+		t.Fatalf("DetectDrift returned error: %v", err)
+	}
+	if report.Drifted {
+		t.Errorf("expected no drift after apply, got:\n%s", report)
+	}
+}
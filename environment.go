@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -38,10 +39,15 @@ type RemoteAgent struct {
 
 // ListTestEnvironments returns all environments associated with a given test
 func (client *Client) ListTestEnvironments(bucketKey string, testID string) ([]Environment, error) {
+	return client.ListTestEnvironmentsWithContext(context.Background(), bucketKey, testID)
+}
+
+// ListTestEnvironmentsWithContext is the same as ListTestEnvironments, bound to the supplied context
+func (client *Client) ListTestEnvironmentsWithContext(ctx context.Context, bucketKey string, testID string) ([]Environment, error) {
 	var environments = []Environment{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/environments", bucketKey, testID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return environments, err
 	}
@@ -52,10 +58,15 @@ func (client *Client) ListTestEnvironments(bucketKey string, testID string) ([]E
 
 // ListSharedEnvironments returns shared environments in a given bucket
 func (client *Client) ListSharedEnvironments(bucketKey string) ([]Environment, error) {
+	return client.ListSharedEnvironmentsWithContext(context.Background(), bucketKey)
+}
+
+// ListSharedEnvironmentsWithContext is the same as ListSharedEnvironments, bound to the supplied context
+func (client *Client) ListSharedEnvironmentsWithContext(ctx context.Context, bucketKey string) ([]Environment, error) {
 	var environments = []Environment{}
 
 	path := fmt.Sprintf("buckets/%s/environments", bucketKey)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return environments, err
 	}
@@ -67,10 +78,15 @@ func (client *Client) ListSharedEnvironments(bucketKey string) ([]Environment, e
 // GetTestEnvironment fetches the details for a given
 // environment associated with a test
 func (client *Client) GetTestEnvironment(bucketKey string, testID string, environmentID string) (Environment, error) {
+	return client.GetTestEnvironmentWithContext(context.Background(), bucketKey, testID, environmentID)
+}
+
+// GetTestEnvironmentWithContext is the same as GetTestEnvironment, bound to the supplied context
+func (client *Client) GetTestEnvironmentWithContext(ctx context.Context, bucketKey string, testID string, environmentID string) (Environment, error) {
 	var environment = Environment{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/environments/%s", bucketKey, testID, environmentID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return environment, err
 	}
@@ -82,10 +98,15 @@ func (client *Client) GetTestEnvironment(bucketKey string, testID string, enviro
 // GetSharedEnvironment fetches the details of a given
 // environment assocaited with a bucket
 func (client *Client) GetSharedEnvironment(bucketKey string, environmentID string) (Environment, error) {
+	return client.GetSharedEnvironmentWithContext(context.Background(), bucketKey, environmentID)
+}
+
+// GetSharedEnvironmentWithContext is the same as GetSharedEnvironment, bound to the supplied context
+func (client *Client) GetSharedEnvironmentWithContext(ctx context.Context, bucketKey string, environmentID string) (Environment, error) {
 	var environment = Environment{}
 
 	path := fmt.Sprintf("buckets/%s/environments/%s", bucketKey, environmentID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return environment, err
 	}
@@ -96,6 +117,11 @@ func (client *Client) GetSharedEnvironment(bucketKey string, environmentID strin
 
 // NewTestEnvironment creates an environment for a given test
 func (client *Client) NewTestEnvironment(bucketKey string, testID string, environment Environment) (Environment, error) {
+	return client.NewTestEnvironmentWithContext(context.Background(), bucketKey, testID, environment)
+}
+
+// NewTestEnvironmentWithContext is the same as NewTestEnvironment, bound to the supplied context
+func (client *Client) NewTestEnvironmentWithContext(ctx context.Context, bucketKey string, testID string, environment Environment) (Environment, error) {
 	var newEnvironment = Environment{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/environments", bucketKey, testID)
@@ -104,7 +130,7 @@ func (client *Client) NewTestEnvironment(bucketKey string, testID string, enviro
 		return newEnvironment, err
 	}
 
-	content, err := client.Post(path, data)
+	content, err := client.PostWithContext(ctx, path, data)
 	if err != nil {
 		return newEnvironment, err
 	}
@@ -115,6 +141,11 @@ func (client *Client) NewTestEnvironment(bucketKey string, testID string, enviro
 
 // NewSharedEnvironment creates a new shared environment in a bucket
 func (client *Client) NewSharedEnvironment(bucketKey string, environment Environment) (Environment, error) {
+	return client.NewSharedEnvironmentWithContext(context.Background(), bucketKey, environment)
+}
+
+// NewSharedEnvironmentWithContext is the same as NewSharedEnvironment, bound to the supplied context
+func (client *Client) NewSharedEnvironmentWithContext(ctx context.Context, bucketKey string, environment Environment) (Environment, error) {
 	var newEnvironment = Environment{}
 
 	path := fmt.Sprintf("buckets/%s/environments", bucketKey)
@@ -123,7 +154,7 @@ func (client *Client) NewSharedEnvironment(bucketKey string, environment Environ
 		return newEnvironment, err
 	}
 
-	content, err := client.Post(path, data)
+	content, err := client.PostWithContext(ctx, path, data)
 	if err != nil {
 		return newEnvironment, err
 	}
@@ -134,6 +165,11 @@ func (client *Client) NewSharedEnvironment(bucketKey string, environment Environ
 
 // UpdateTestEnvironment updates a test environment
 func (client *Client) UpdateTestEnvironment(bucketKey string, testID string, environmentID string, environment Environment) (Environment, error) {
+	return client.UpdateTestEnvironmentWithContext(context.Background(), bucketKey, testID, environmentID, environment)
+}
+
+// UpdateTestEnvironmentWithContext is the same as UpdateTestEnvironment, bound to the supplied context
+func (client *Client) UpdateTestEnvironmentWithContext(ctx context.Context, bucketKey string, testID string, environmentID string, environment Environment) (Environment, error) {
 	var newEnvironment = Environment{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/environments/%s", bucketKey, testID, environmentID)
@@ -142,7 +178,7 @@ func (client *Client) UpdateTestEnvironment(bucketKey string, testID string, env
 		return newEnvironment, err
 	}
 
-	content, err := client.Put(path, data)
+	content, err := client.PutWithContext(ctx, path, data)
 	if err != nil {
 		return newEnvironment, err
 	}
@@ -153,6 +189,11 @@ func (client *Client) UpdateTestEnvironment(bucketKey string, testID string, env
 
 // UpdateSharedEnvironment updates a shared environment in a bucket
 func (client *Client) UpdateSharedEnvironment(bucketKey string, environmentID string, environment Environment) (Environment, error) {
+	return client.UpdateSharedEnvironmentWithContext(context.Background(), bucketKey, environmentID, environment)
+}
+
+// UpdateSharedEnvironmentWithContext is the same as UpdateSharedEnvironment, bound to the supplied context
+func (client *Client) UpdateSharedEnvironmentWithContext(ctx context.Context, bucketKey string, environmentID string, environment Environment) (Environment, error) {
 	var newEnvironment = Environment{}
 
 	path := fmt.Sprintf("buckets/%s/environments/%s", bucketKey, environmentID)
@@ -161,7 +202,7 @@ func (client *Client) UpdateSharedEnvironment(bucketKey string, environmentID st
 		return newEnvironment, err
 	}
 
-	content, err := client.Put(path, data)
+	content, err := client.PutWithContext(ctx, path, data)
 	if err != nil {
 		return newEnvironment, err
 	}
@@ -172,6 +213,11 @@ func (client *Client) UpdateSharedEnvironment(bucketKey string, environmentID st
 
 // DeleteEnvironment removes an environment from a bucket
 func (client *Client) DeleteEnvironment(bucketKey string, environmentID string) error {
+	return client.DeleteEnvironmentWithContext(context.Background(), bucketKey, environmentID)
+}
+
+// DeleteEnvironmentWithContext is the same as DeleteEnvironment, bound to the supplied context
+func (client *Client) DeleteEnvironmentWithContext(ctx context.Context, bucketKey string, environmentID string) error {
 	path := fmt.Sprintf("buckets/%s/environments/%s", bucketKey, environmentID)
-	return client.Delete(path)
+	return client.DeleteWithContext(ctx, path)
 }
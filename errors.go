@@ -0,0 +1,101 @@
+package runscope
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIError is returned when the Runscope API responds with a non-2xx
+// status code or a response envelope containing an error
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Method and Path identify the request that failed
+	Method string
+	Path   string
+	// ErrorStatus and Message are taken from the "error" object
+	// of the Runscope response envelope, when present
+	ErrorStatus int
+	Message     string
+	// MetaStatus is taken from the "meta" object of the response envelope
+	MetaStatus string
+	// Body is the raw response body
+	Body []byte
+	// RetryAfter is how long Runscope asked the client to wait before
+	// sending another request, taken from the Retry-After or
+	// X-RateLimit-Reset response headers
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+
+	status := e.StatusCode
+	if e.ErrorStatus != 0 {
+		status = e.ErrorStatus
+	}
+
+	if e.Method == "" {
+		return fmt.Sprintf("%s (%d)", message, status)
+	}
+	return fmt.Sprintf("%s %s: %s (%d)", e.Method, e.Path, message, status)
+}
+
+// status returns the most specific status code known for the error,
+// preferring the envelope status when the response itself succeeded
+func (e *APIError) status() int {
+	if e.StatusCode != 0 && (e.StatusCode < 200 || e.StatusCode >= 300 || e.ErrorStatus == 0) {
+		return e.StatusCode
+	}
+	return e.ErrorStatus
+}
+
+// newAPIError builds an APIError from a raw response body, filling in
+// the Runscope error envelope fields if the body can be decoded
+func newAPIError(code int, method string, path string, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: code,
+		Method:     method,
+		Path:       path,
+		Body:       body,
+	}
+
+	var response Response
+	if err := json.Unmarshal(body, &response); err == nil {
+		apiErr.ErrorStatus = response.Error.Status
+		apiErr.Message = response.Error.Message
+		apiErr.MetaStatus = response.Meta.Status
+	}
+	return apiErr
+}
+
+// IsNotFound reports whether err is an APIError for a missing resource
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is an APIError caused by a
+// missing or invalid token
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsRateLimited reports whether err is an APIError caused by
+// exceeding the Runscope rate limit
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, code int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.status() == code || apiErr.ErrorStatus == code
+}
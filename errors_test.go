@@ -0,0 +1,106 @@
+package runscope
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	responseData := `
+{
+  "data": null,
+  "error": {
+    "status": 404,
+    "message": "Bucket not found"
+  },
+  "meta": {
+    "status": "error"
+  }
+}`
+	handleGet(t, "/buckets/1", http.StatusNotFound, responseData)
+
+	_, err := client.GetBucket("1")
+	if !IsNotFound(err) {
+		t.Fatalf("GetBucket returned %v, want a not found error", err)
+	}
+	if IsUnauthorized(err) || IsRateLimited(err) {
+		t.Errorf("GetBucket error %v should only be a not found error", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetBucket returned %T, want *APIError", err)
+	}
+	want := &APIError{
+		StatusCode:  http.StatusNotFound,
+		Method:      "GET",
+		Path:        "/buckets/1",
+		ErrorStatus: 404,
+		Message:     "Bucket not found",
+		MetaStatus:  "error",
+		Body:        []byte(responseData),
+	}
+	testResponseData(t, apiErr, want)
+
+	if got, want := err.Error(), "GET /buckets/1: Bucket not found (404)"; got != want {
+		t.Errorf("Error() returned %q, want %q", got, want)
+	}
+}
+
+func TestAPIErrorUnauthorized(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleGet(t, "/account", http.StatusUnauthorized, "")
+
+	_, err := client.GetAccount()
+	if !IsUnauthorized(err) {
+		t.Fatalf("GetAccount returned %v, want an unauthorized error", err)
+	}
+	if got, want := err.Error(), "GET /account: Unauthorized (401)"; got != want {
+		t.Errorf("Error() returned %q, want %q", got, want)
+	}
+}
+
+func TestAPIErrorEnvelope(t *testing.T) {
+	setup()
+	defer teardown()
+
+	responseData := `
+{
+  "data": null,
+  "error": {
+    "status": 429,
+    "message": "Rate limit exceeded"
+  },
+  "meta": {
+    "status": "error"
+  }
+}`
+	handleGet(t, "/buckets", http.StatusOK, responseData)
+
+	_, err := client.ListBuckets()
+	if !IsRateLimited(err) {
+		t.Fatalf("ListBuckets returned %v, want a rate limited error", err)
+	}
+	if got, want := err.Error(), "GET /buckets: Rate limit exceeded (429)"; got != want {
+		t.Errorf("Error() returned %q, want %q", got, want)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr); apiErr.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusOK)
+	}
+}
+
+func TestIsNotFoundWithOtherErrors(t *testing.T) {
+	if IsNotFound(nil) {
+		t.Error("IsNotFound(nil) should be false")
+	}
+	if IsNotFound(errors.New("404")) {
+		t.Error("IsNotFound should be false for non APIError errors")
+	}
+}
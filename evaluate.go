@@ -0,0 +1,355 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StepResponse is a HTTP response that the assertions and variables of a
+// step are evaluated against
+type StepResponse struct {
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+	ResponseTime time.Duration
+}
+
+// NewStepResponse reads a HTTP response, which took elapsed to receive,
+// into a StepResponse. The response body is consumed and closed.
+func NewStepResponse(res *http.Response, elapsed time.Duration) (StepResponse, error) {
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	return StepResponse{
+		StatusCode:   res.StatusCode,
+		Header:       res.Header,
+		Body:         body,
+		ResponseTime: elapsed,
+	}, err
+}
+
+// missing is the actual value of a JSON property that does not exist
+var missing = &struct{}{}
+
+// EvaluateStep evaluates the assertions and extracts the variables of a
+// request step against a response, returning a Request with the
+// Result, ActualValue and Error of each assertion and variable populated
+// the way Runscope reports them. Scripts are not evaluated.
+func EvaluateStep(step Step, response StepResponse) Request {
+	request := Request{
+		Result:            "pass",
+		URL:               step.URL,
+		Method:            step.Method,
+		AssertionsDefined: len(step.Assertions),
+		VariablesDefined:  len(step.Variables),
+		ScriptsDefined:    len(step.Scripts),
+		Assertions:        []Assertion{},
+		Variables:         []Variable{},
+		Scripts:           []Script{},
+	}
+
+	for _, assertion := range step.Assertions {
+		evaluated := EvaluateAssertion(assertion, response)
+		request.Assertions = append(request.Assertions, evaluated)
+		if evaluated.Result == "pass" {
+			request.AssertionsPassed++
+		} else {
+			request.AssertionsFailed++
+			request.Result = "fail"
+		}
+	}
+
+	for _, variable := range step.Variables {
+		extracted := ExtractVariable(variable, response)
+		request.Variables = append(request.Variables, extracted)
+		if extracted.Result == "pass" {
+			request.VariablesPassed++
+		} else {
+			request.VariablesFailed++
+			request.Result = "fail"
+		}
+	}
+	return request
+}
+
+// EvaluateAssertion evaluates a single assertion against a response
+func EvaluateAssertion(assertion Assertion, response StepResponse) Assertion {
+	assertion.TargetValue = assertion.Value
+	assertion.Result = "fail"
+
+	actual, err := sourceValue(Source(assertion.Source), assertion.Property, response)
+	if err != nil {
+		assertion.Error = err.Error()
+		return assertion
+	}
+	if actual != missing {
+		assertion.ActualValue = actual
+	}
+
+	passed, err := compare(Comparison(assertion.Comparison), actual, assertion.Value)
+	if err != nil {
+		assertion.Error = err.Error()
+		return assertion
+	}
+	if passed {
+		assertion.Result = "pass"
+	}
+	return assertion
+}
+
+// ExtractVariable extracts a variable's value from a response
+func ExtractVariable(variable Variable, response StepResponse) Variable {
+	variable.Result = "fail"
+
+	var value interface{}
+	var err error
+	switch Source(variable.Source) {
+	case SourceCookies:
+		value = missing
+		for _, cookie := range (&http.Response{Header: response.Header}).Cookies() {
+			if cookie.Name == variable.Property {
+				value = cookie.Value
+			}
+		}
+	case SourceText:
+		value, err = matchText(variable.Property, response.Body)
+	default:
+		value, err = sourceValue(Source(variable.Source), variable.Property, response)
+	}
+
+	if err != nil {
+		variable.Error = err.Error()
+		return variable
+	}
+	if value == missing {
+		variable.Error = fmt.Sprintf("%s not found in %s", variable.Property, variable.Source)
+		return variable
+	}
+
+	variable.Value = stringify(value)
+	variable.Result = "pass"
+	return variable
+}
+
+// sourceValue returns the part of the response a source refers to
+func sourceValue(source Source, property string, response StepResponse) (interface{}, error) {
+	switch source {
+	case SourceStatus:
+		return response.StatusCode, nil
+	case SourceHeaders:
+		values, ok := response.Header[http.CanonicalHeaderKey(property)]
+		if !ok {
+			return missing, nil
+		}
+		return strings.Join(values, ", "), nil
+	case SourceText:
+		return string(response.Body), nil
+	case SourceResponseTime:
+		return float64(response.ResponseTime) / float64(time.Millisecond), nil
+	case SourceResponseSize:
+		return len(response.Body), nil
+	case SourceJSON:
+		var document interface{}
+		decoder := json.NewDecoder(bytes.NewReader(response.Body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&document); err != nil {
+			return nil, fmt.Errorf("Response body is not valid JSON: %v", err)
+		}
+		return jsonPath(document, property)
+	}
+	return nil, fmt.Errorf("Source %q is not supported locally", source)
+}
+
+// jsonPathSegment matches a path segment with optional indexes, e.g. users[0]
+var jsonPathSegment = regexp.MustCompile(`^([^\[\]]*)((?:\[\d+\])*)$`)
+
+var jsonPathIndex = regexp.MustCompile(`\d+`)
+
+// jsonPath resolves a Runscope property path such as data.users[0].id
+// in a decoded JSON document. Missing properties resolve to missing.
+func jsonPath(document interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return document, nil
+	}
+
+	current := document
+	for _, segment := range strings.Split(path, ".") {
+		match := jsonPathSegment.FindStringSubmatch(segment)
+		if match == nil {
+			return nil, fmt.Errorf("Invalid JSON property %q", path)
+		}
+
+		if match[1] != "" {
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				if index, err := strconv.Atoi(match[1]); err == nil {
+					match[2] = fmt.Sprintf("[%d]%s", index, match[2])
+				} else {
+					return missing, nil
+				}
+			} else if current, ok = object[match[1]]; !ok {
+				return missing, nil
+			}
+		}
+
+		for _, index := range jsonPathIndex.FindAllString(match[2], -1) {
+			array, ok := current.([]interface{})
+			i, _ := strconv.Atoi(index)
+			if !ok || i >= len(array) {
+				return missing, nil
+			}
+			current = array[i]
+		}
+	}
+	return current, nil
+}
+
+// matchText returns the first capture group of pattern in body, or the
+// whole match when the pattern has no groups
+func matchText(pattern string, body []byte) (interface{}, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	match := re.FindSubmatch(body)
+	if match == nil {
+		return missing, nil
+	}
+	if len(match) > 1 {
+		return string(match[1]), nil
+	}
+	return string(match[0]), nil
+}
+
+// compare applies a comparison to the actual and expected values
+func compare(comparison Comparison, actual interface{}, expected interface{}) (bool, error) {
+	switch comparison {
+	case ComparisonIsEmpty:
+		return isEmpty(actual), nil
+	case ComparisonIsNotEmpty:
+		return actual != missing && !isEmpty(actual), nil
+	case ComparisonIsNull:
+		return actual == nil, nil
+	case ComparisonIsNumber:
+		_, ok := toNumber(actual)
+		return ok, nil
+	case ComparisonEqual:
+		return actual != missing && stringify(actual) == stringify(expected), nil
+	case ComparisonNotEqual:
+		return actual == missing || stringify(actual) != stringify(expected), nil
+	case ComparisonContains:
+		return actual != missing && strings.Contains(stringify(actual), stringify(expected)), nil
+	case ComparisonDoesNotContain:
+		return actual == missing || !strings.Contains(stringify(actual), stringify(expected)), nil
+	case ComparisonHasKey:
+		object, ok := actual.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		_, ok = object[stringify(expected)]
+		return ok, nil
+	case ComparisonHasValue:
+		return hasValue(actual, expected), nil
+	case ComparisonEqualNumber, ComparisonLessThan, ComparisonLessThanOrEqual, ComparisonGreaterThan, ComparisonGreaterThanOrEqual:
+		target, ok := toNumber(expected)
+		if !ok {
+			return false, fmt.Errorf("Target value %v is not a number", expected)
+		}
+		value, ok := toNumber(actual)
+		if !ok {
+			return false, nil
+		}
+		switch comparison {
+		case ComparisonEqualNumber:
+			return value == target, nil
+		case ComparisonLessThan:
+			return value < target, nil
+		case ComparisonLessThanOrEqual:
+			return value <= target, nil
+		case ComparisonGreaterThan:
+			return value > target, nil
+		default:
+			return value >= target, nil
+		}
+	}
+	return false, fmt.Errorf("Comparison %q is not supported", comparison)
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil || value == missing {
+		return true
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+func hasValue(container interface{}, expected interface{}) bool {
+	var values []interface{}
+	switch v := container.(type) {
+	case []interface{}:
+		values = v
+	case map[string]interface{}:
+		for _, value := range v {
+			values = append(values, value)
+		}
+	}
+	for _, value := range values {
+		if stringify(value) == stringify(expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// toNumber converts numbers and numeric strings to float64
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// stringify returns the string form Runscope compares values by
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	if value == missing {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,106 @@
+package runscope
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+var evaluateResponse = StepResponse{
+	StatusCode: 201,
+	Header: http.Header{
+		"Content-Type": {"application/json"},
+		"Set-Cookie":   {"session=abc123; Path=/"},
+	},
+	Body:         []byte(`{"data": {"id": 42, "name": "Grace", "tags": ["a", "b"], "users": [{"id": 7}], "deleted": null}}`),
+	ResponseTime: 120 * time.Millisecond,
+}
+
+func TestEvaluateAssertion(t *testing.T) {
+	tests := []struct {
+		assertion Assertion
+		result    string
+	}{
+		{AssertStatus().Equals(201), "pass"},
+		{AssertStatus().LessThan(300), "pass"},
+		{AssertStatus().Equals(200), "fail"},
+		{AssertHeader("content-type").Contains("json"), "pass"},
+		{AssertHeader("X-Missing").NotEmpty(), "fail"},
+		{AssertJSON("data.id").Equals("42"), "pass"},
+		{AssertJSON("data.id").GreaterThan(40), "pass"},
+		{AssertJSON("data.name").Equals("Grace"), "pass"},
+		{AssertJSON("data.users[0].id").Equals(7), "pass"},
+		{AssertJSON("data.users[1].id").IsEmpty(), "pass"},
+		{AssertJSON("data.tags").HasValue("b"), "pass"},
+		{AssertJSON("data").HasKey("name"), "pass"},
+		{AssertJSON("data").HasKey("email"), "fail"},
+		{AssertJSON("data.deleted").IsNull(), "pass"},
+		{AssertJSON("data.name").IsNumber(), "fail"},
+		{AssertText().DoesNotContain("error"), "pass"},
+		{AssertResponseTime().LessThan(500), "pass"},
+		{AssertResponseTime().LessThan(100), "fail"},
+		{AssertResponseSize().GreaterThan(10), "pass"},
+	}
+
+	for _, test := range tests {
+		got := EvaluateAssertion(test.assertion, evaluateResponse)
+		if got.Result != test.result {
+			t.Errorf("EvaluateAssertion(%+v) returned %s (actual %v, error %q), expected %s",
+				test.assertion, got.Result, got.ActualValue, got.Error, test.result)
+		}
+	}
+}
+
+func TestEvaluateAssertionErrors(t *testing.T) {
+	got := EvaluateAssertion(AssertXML("/user").IsNull(), evaluateResponse)
+	if got.Result != "fail" || got.Error == "" {
+		t.Errorf("expected unsupported source to fail with an error, got %+v", got)
+	}
+
+	response := StepResponse{StatusCode: 200, Body: []byte("not json")}
+	got = EvaluateAssertion(AssertJSON("id").NotEmpty(), response)
+	if got.Result != "fail" || got.Error == "" {
+		t.Errorf("expected invalid JSON to fail with an error, got %+v", got)
+	}
+}
+
+func TestEvaluateStep(t *testing.T) {
+	step := Step{
+		StepType: "request",
+		Method:   "POST",
+		URL:      "https://api.example.com/users",
+		Assertions: []Assertion{
+			AssertStatus().Equals(201),
+			AssertJSON("data.name").Equals("Ada"),
+		},
+		Variables: []Variable{
+			VariableFromJSON("user_id", "data.id"),
+			VariableFromHeader("content_type", "Content-Type"),
+			VariableFromCookie("session", "session"),
+			VariableFromStatus("status"),
+			VariableFromText("name", `"name": "(\w+)"`),
+			VariableFromJSON("email", "data.email"),
+		},
+	}
+
+	request := EvaluateStep(step, evaluateResponse)
+	if request.Result != "fail" {
+		t.Errorf("expected result fail, got %s", request.Result)
+	}
+	if request.AssertionsPassed != 1 || request.AssertionsFailed != 1 {
+		t.Errorf("expected 1 passed and 1 failed assertion, got %d and %d", request.AssertionsPassed, request.AssertionsFailed)
+	}
+	if request.VariablesPassed != 5 || request.VariablesFailed != 1 {
+		t.Errorf("expected 5 passed and 1 failed variable, got %d and %d", request.VariablesPassed, request.VariablesFailed)
+	}
+
+	want := []interface{}{"42", "application/json", "abc123", "201", "Grace", nil}
+	for i, variable := range request.Variables {
+		if variable.Value != want[i] {
+			t.Errorf("expected variable %s to be %v, got %v", variable.Name, want[i], variable.Value)
+		}
+	}
+	if request.Variables[5].Error == "" {
+		t.Errorf("expected missing variable to have an error")
+	}
+}
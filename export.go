@@ -0,0 +1,322 @@
+package runscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Files and directories of a bucket export
+const (
+	ExportBucketFile      = "bucket.json"
+	ExportEnvironmentsDir = "environments"
+	ExportTestsDir        = "tests"
+)
+
+// ExportBucket writes a bucket into dir as pretty-printed JSON files:
+//
+//	bucket.json               bucket metadata, without its auth token
+//	environments/<name>.json  one file per shared environment
+//	tests/<name>.json         one file per test, with its steps,
+//	                          environments and schedules
+//
+// IDs are left out and environments are referred to by name, so that an
+// export only changes when the bucket does and can be imported into
+// another bucket. Files are in the format of BucketConfig, and existing
+// environment and test files in dir are replaced.
+func (client *Client) ExportBucket(ctx context.Context, bucketKey string, dir string) error {
+	bucket, err := client.GetBucketWithContext(ctx, bucketKey)
+	if err != nil {
+		return err
+	}
+
+	sharedEnvironments, err := client.ListSharedEnvironmentsWithContext(ctx, bucketKey)
+	if err != nil {
+		return err
+	}
+	names := environmentIDNames(sharedEnvironments)
+
+	tests, err := client.ListAllTestsWithContext(ctx, bucketKey)
+	if err != nil {
+		return err
+	}
+
+	exported := make([]TestConfig, 0, len(tests))
+	for _, summary := range tests {
+		test, err := client.GetTestWithContext(ctx, bucketKey, summary.ID)
+		if err != nil {
+			return err
+		}
+		schedules, err := client.ListSchedulesWithContext(ctx, bucketKey, test.ID)
+		if err != nil {
+			return err
+		}
+		exported = append(exported, exportTest(test, schedules, names))
+	}
+
+	for _, sub := range []string{ExportEnvironmentsDir, ExportTestsDir} {
+		if err := os.RemoveAll(filepath.Join(dir, sub)); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	bucket.AuthToken = ""
+	if err := writeJSONFile(filepath.Join(dir, ExportBucketFile), bucket); err != nil {
+		return err
+	}
+
+	environments := exportEnvironments(sharedEnvironments, nil)
+	files := exportFileNames(len(environments), func(i int) string { return environments[i].Name })
+	for i, environment := range environments {
+		if err := writeJSONFile(filepath.Join(dir, ExportEnvironmentsDir, files[i]), environment); err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(exported, func(i, j int) bool { return exported[i].Name < exported[j].Name })
+	files = exportFileNames(len(exported), func(i int) string { return exported[i].Name })
+	for i, test := range exported {
+		if err := writeJSONFile(filepath.Join(dir, ExportTestsDir, files[i]), test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBucketExport reads a directory written by ExportBucket. The result
+// can be applied with ApplyConfig to keep a bucket in sync with an export.
+func ReadBucketExport(dir string) (BucketConfig, error) {
+	var bucket Bucket
+	if err := readJSONFile(filepath.Join(dir, ExportBucketFile), &bucket); err != nil {
+		return BucketConfig{}, err
+	}
+
+	config := BucketConfig{Name: bucket.Name, Key: bucket.Key, TeamUUID: bucket.Team.UUID}
+	err := readJSONDir(filepath.Join(dir, ExportEnvironmentsDir), func(path string) error {
+		var environment EnvironmentConfig
+		err := readJSONFile(path, &environment)
+		config.Environments = append(config.Environments, environment)
+		return err
+	})
+	if err != nil {
+		return config, err
+	}
+
+	err = readJSONDir(filepath.Join(dir, ExportTestsDir), func(path string) error {
+		var test TestConfig
+		err := readJSONFile(path, &test)
+		config.Tests = append(config.Tests, test)
+		return err
+	})
+	if err != nil {
+		return config, err
+	}
+	return config, Config{Buckets: []BucketConfig{config}}.Validate()
+}
+
+// ImportBucket recreates an export written by ExportBucket in a bucket,
+// creating its shared environments with NewSharedEnvironment, its tests
+// with ImportTest and their schedules with NewSchedule. When bucketKey is
+// empty, a new bucket is created from the exported metadata.
+func (client *Client) ImportBucket(ctx context.Context, bucketKey string, dir string) (Bucket, error) {
+	config, err := ReadBucketExport(dir)
+	if err != nil {
+		return Bucket{}, err
+	}
+
+	var bucket Bucket
+	if bucketKey == "" {
+		bucket, err = client.NewBucketWithContext(ctx, &NewBucketRequest{Name: config.Name, TeamUUID: config.TeamUUID})
+	} else {
+		bucket, err = client.GetBucketWithContext(ctx, bucketKey)
+	}
+	if err != nil {
+		return bucket, err
+	}
+
+	shared := map[string]string{}
+	for _, environment := range config.Environments {
+		environment.ID = ""
+		created, err := client.NewSharedEnvironmentWithContext(ctx, bucket.Key, environment.Environment)
+		if err != nil {
+			return bucket, err
+		}
+		shared[environment.Name] = created.ID
+	}
+
+	for _, test := range config.Tests {
+		if err := client.importTest(ctx, bucket.Key, test, shared); err != nil {
+			return bucket, fmt.Errorf("test %q: %w", test.Name, err)
+		}
+	}
+	return bucket, nil
+}
+
+func (client *Client) importTest(ctx context.Context, bucketKey string, test TestConfig, shared map[string]string) error {
+	definition := Test{
+		Name:         test.Name,
+		Description:  test.Description,
+		Steps:        test.Steps,
+		Environments: []Environment{},
+	}
+	for _, environment := range test.Environments {
+		environment.ID = ""
+		environment.ParentEnvironmentID = shared[environment.Parent]
+		definition.Environments = append(definition.Environments, environment.Environment)
+	}
+
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return err
+	}
+	imported, err := client.ImportTestWithContext(ctx, bucketKey, data)
+	if err != nil {
+		return err
+	}
+
+	environments := map[string]string{}
+	for name, id := range shared {
+		environments[name] = id
+	}
+	for _, environment := range imported.Environments {
+		environments[environment.Name] = environment.ID
+	}
+
+	if test.DefaultEnvironment != "" && environments[test.DefaultEnvironment] != imported.DefaultEnvironmentID {
+		update := UpdateTestRequest{DefaultEnvironmentID: environments[test.DefaultEnvironment]}
+		if _, err := client.UpdateTestWithContext(ctx, bucketKey, imported.ID, update); err != nil {
+			return err
+		}
+	}
+
+	for _, schedule := range test.Schedules {
+		request := Schedule{Note: schedule.Note, Interval: schedule.Interval, EnvironmentID: environments[schedule.Environment]}
+		if _, err := client.NewScheduleWithContext(ctx, bucketKey, imported.ID, request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportTest converts a test into its exported form, without IDs
+func exportTest(test Test, schedules []Schedule, shared map[string]string) TestConfig {
+	names := environmentIDNames(test.Environments)
+	for id, name := range shared {
+		names[id] = name
+	}
+
+	exported := TestConfig{
+		Name:               test.Name,
+		Description:        test.Description,
+		DefaultEnvironment: names[test.DefaultEnvironmentID],
+		Environments:       exportEnvironments(test.Environments, shared),
+	}
+	for _, step := range test.Steps {
+		exported.Steps = append(exported.Steps, stepView(step))
+	}
+
+	for _, schedule := range schedules {
+		exported.Schedules = append(exported.Schedules, ScheduleConfig{
+			Environment: names[schedule.EnvironmentID],
+			Interval:    schedule.Interval,
+			Note:        schedule.Note,
+		})
+	}
+	sort.SliceStable(exported.Schedules, func(i, j int) bool {
+		a, b := exported.Schedules[i], exported.Schedules[j]
+		if a.Environment != b.Environment {
+			return a.Environment < b.Environment
+		}
+		return a.Interval < b.Interval
+	})
+	return exported
+}
+
+// exportEnvironments sorts environments by name and replaces their IDs
+// with the name of their parent
+func exportEnvironments(environments []Environment, parents map[string]string) []EnvironmentConfig {
+	exported := make([]EnvironmentConfig, 0, len(environments))
+	for _, environment := range environments {
+		parent := parents[environment.ParentEnvironmentID]
+		environment.ID = ""
+		environment.TestID = ""
+		environment.ParentEnvironmentID = ""
+		exported = append(exported, EnvironmentConfig{Environment: environment, Parent: parent})
+	}
+	sort.SliceStable(exported, func(i, j int) bool { return exported[i].Name < exported[j].Name })
+	return exported
+}
+
+var nonFileCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// exportFileNames returns a unique file name for each of n named
+// resources, numbering resources whose names collide with a file name
+// that is already taken
+func exportFileNames(n int, name func(int) string) []string {
+	files := make([]string, n)
+	used := map[string]bool{}
+	for i := range files {
+		base := strings.Trim(nonFileCharacters.ReplaceAllString(strings.ToLower(name(i)), "-"), "-")
+		if base == "" {
+			base = "unnamed"
+		}
+		file := base
+		for suffix := 2; used[file]; suffix++ {
+			file = fmt.Sprintf("%s-%d", base, suffix)
+		}
+		used[file] = true
+		files[i] = file + ".json"
+	}
+	return files
+}
+
+// writeJSONFile writes the canonical form of a value, which has sorted
+// keys and no empty fields, as indented JSON
+func writeJSONFile(path string, value interface{}) error {
+	data, err := json.MarshalIndent(canonical(value), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func readJSONFile(path string, value interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// readJSONDir calls fn with each JSON file in dir, in name order. A
+// missing directory has no files.
+func readJSONDir(dir string, fn func(path string) error) error {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if err := fn(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
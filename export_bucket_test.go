@@ -0,0 +1,92 @@
+package runscope_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	runscope "github.com/nextrevision/go-runscope"
+	"github.com/nextrevision/go-runscope/runscopetest"
+)
+
+func readExport(t *testing.T, dir string) map[string]string {
+	files := map[string]string{}
+	for _, pattern := range []string{"environments/*.json", "tests/*.json"} {
+		paths, _ := filepath.Glob(filepath.Join(dir, pattern))
+		for _, path := range paths {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile returned error: %v", err)
+			}
+			rel, _ := filepath.Rel(dir, path)
+			files[rel] = string(data)
+		}
+	}
+	return files
+}
+
+func TestExportBucket(t *testing.T) {
+	server := runscopetest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	config, _ := runscope.ParseConfig([]byte(desiredConfig))
+	if _, err := client.ApplyConfig(ctx, config, &bytes.Buffer{}); err != nil {
+		t.Fatalf("ApplyConfig returned error: %v", err)
+	}
+	buckets, _ := client.ListBuckets()
+	key := buckets[0].Key
+
+	dir := t.TempDir()
+	if err := client.ExportBucket(ctx, key, dir); err != nil {
+		t.Fatalf("ExportBucket returned error: %v", err)
+	}
+	files := readExport(t, dir)
+	if len(files) != 2 || files["environments/production.json"] == "" || files["tests/login.json"] == "" {
+		t.Fatalf("unexpected exported files %v", files)
+	}
+	if strings.Contains(files["tests/login.json"], `"id"`) || !strings.Contains(files["tests/login.json"], `"parent": "Production"`) {
+		t.Errorf("expected export without IDs and with parents by name:\n%s", files["tests/login.json"])
+	}
+	bucketFile, _ := ioutil.ReadFile(filepath.Join(dir, "bucket.json"))
+	if strings.Contains(string(bucketFile), "auth_token") {
+		t.Errorf("expected bucket export without auth token:\n%s", bucketFile)
+	}
+
+	// exporting again gives identical files
+	again := t.TempDir()
+	client.ExportBucket(ctx, key, again)
+	if !reflect.DeepEqual(files, readExport(t, again)) {
+		t.Errorf("expected exports to be identical")
+	}
+
+	exported, err := runscope.ReadBucketExport(dir)
+	if err != nil {
+		t.Fatalf("ReadBucketExport returned error: %v", err)
+	}
+	plan, err := client.PlanConfig(ctx, runscope.Config{Buckets: []runscope.BucketConfig{exported}})
+	if err != nil || !plan.Empty() {
+		t.Errorf("expected export to match the bucket, got %v:\n%s", err, plan)
+	}
+
+	imported, err := client.ImportBucket(ctx, "", dir)
+	if err != nil {
+		t.Fatalf("ImportBucket returned error: %v", err)
+	}
+	if imported.Key == key || imported.Name != "Mobile Apps" {
+		t.Errorf("expected a new bucket, got %+v", imported)
+	}
+
+	roundTrip := t.TempDir()
+	if err := client.ExportBucket(ctx, imported.Key, roundTrip); err != nil {
+		t.Fatalf("ExportBucket returned error: %v", err)
+	}
+	if got := readExport(t, roundTrip); !reflect.DeepEqual(files, got) {
+		t.Errorf("expected imported bucket to export the same files, got %v", got)
+	}
+}
@@ -0,0 +1,24 @@
+package runscope
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExportFileNames(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  []string
+	}{
+		{[]string{"Login", "Sign Up!", ""}, []string{"login.json", "sign-up.json", "unnamed.json"}},
+		{[]string{"a", "a", "a 2"}, []string{"a.json", "a-2.json", "a-2-2.json"}},
+		{[]string{"a 2", "a", "a", "A"}, []string{"a-2.json", "a.json", "a-3.json", "a-4.json"}},
+	}
+
+	for _, test := range tests {
+		got := exportFileNames(len(test.names), func(i int) string { return test.names[i] })
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("exportFileNames(%q) returned %q, want %q", test.names, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/nextrevision/go-runscope
+
+go 1.21
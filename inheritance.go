@@ -0,0 +1,145 @@
+package runscope
+
+import (
+	"context"
+	"fmt"
+)
+
+// ValueSource identifies the environment a resolved value came from
+type ValueSource struct {
+	EnvironmentID   string `json:"environment_id"`
+	EnvironmentName string `json:"environment_name"`
+	Shared          bool   `json:"shared"`
+}
+
+// ResolvedEnvironment is the effective environment of a test run, merged
+// from a test environment and the shared environments it inherits from
+type ResolvedEnvironment struct {
+	// Environment is the merged environment, with the ID and name of the
+	// environment that was resolved
+	Environment Environment `json:"environment"`
+	// Chain lists the environment that was resolved followed by each of
+	// its parents
+	Chain []Environment `json:"chain"`
+	// Variables records the source of each initial variable
+	Variables map[string]ValueSource `json:"variables"`
+	// Settings records the source of each setting, keyed by its JSON
+	// name, e.g. regions or verify_ssl
+	Settings map[string]ValueSource `json:"settings"`
+}
+
+// ResolveEnvironment returns the effective environment of a test, merging
+// a test or shared environment with the shared environments it inherits
+// from
+func (client *Client) ResolveEnvironment(bucketKey string, testID string, environmentID string) (ResolvedEnvironment, error) {
+	return client.ResolveEnvironmentWithContext(context.Background(), bucketKey, testID, environmentID)
+}
+
+// ResolveEnvironmentWithContext is the same as ResolveEnvironment, bound to the supplied context
+func (client *Client) ResolveEnvironmentWithContext(ctx context.Context, bucketKey string, testID string, environmentID string) (ResolvedEnvironment, error) {
+	shared, err := client.ListSharedEnvironmentsWithContext(ctx, bucketKey)
+	if err != nil {
+		return ResolvedEnvironment{}, err
+	}
+
+	environments, err := client.ListTestEnvironmentsWithContext(ctx, bucketKey, testID)
+	if err != nil {
+		return ResolvedEnvironment{}, err
+	}
+
+	for _, environment := range append(environments, shared...) {
+		if environment.ID == environmentID {
+			return ResolveEnvironmentChain(environment, shared)
+		}
+	}
+	return ResolvedEnvironment{}, fmt.Errorf("Environment %s not found for test %s", environmentID, testID)
+}
+
+// ResolveEnvironmentChain merges an environment with its parents, which
+// are looked up by ID in shared. Initial variables are merged with the
+// closest environment taking precedence, and other settings are taken
+// from the closest environment that sets them. VerifySSL and
+// PreserveCookies are always taken from the environment itself.
+func ResolveEnvironmentChain(environment Environment, shared []Environment) (ResolvedEnvironment, error) {
+	byID := make(map[string]Environment, len(shared))
+	for _, parent := range shared {
+		byID[parent.ID] = parent
+	}
+
+	chain := []Environment{environment}
+	seen := map[string]bool{environment.ID: true}
+	for current := environment; current.ParentEnvironmentID != ""; {
+		parent, ok := byID[current.ParentEnvironmentID]
+		if !ok {
+			return ResolvedEnvironment{}, fmt.Errorf("Parent environment %s of %s not found", current.ParentEnvironmentID, current.ID)
+		}
+		if seen[parent.ID] {
+			return ResolvedEnvironment{}, fmt.Errorf("Environment %s inherits from itself", parent.ID)
+		}
+		seen[parent.ID] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+
+	resolved := ResolvedEnvironment{
+		Environment: Environment{
+			ID:               environment.ID,
+			Name:             environment.Name,
+			TestID:           environment.TestID,
+			VerifySSL:        environment.VerifySSL,
+			PreserveCookies:  environment.PreserveCookies,
+			InitialVariables: map[string]string{},
+		},
+		Chain:     chain,
+		Variables: map[string]ValueSource{},
+		Settings: map[string]ValueSource{
+			"verify_ssl":       sourceOf(environment),
+			"preserve_cookies": sourceOf(environment),
+		},
+	}
+
+	// walk from the root so that closer environments override
+	merged := &resolved.Environment
+	for i := len(chain) - 1; i >= 0; i-- {
+		layer := chain[i]
+		source := sourceOf(layer)
+
+		for name, value := range layer.InitialVariables {
+			merged.InitialVariables[name] = value
+			resolved.Variables[name] = source
+		}
+		if len(layer.Regions) > 0 {
+			merged.Regions = layer.Regions
+			resolved.Settings["regions"] = source
+		}
+		if len(layer.RemoteAgents) > 0 {
+			merged.RemoteAgents = layer.RemoteAgents
+			resolved.Settings["remote_agents"] = source
+		}
+		if len(layer.Webhooks) > 0 {
+			merged.Webhooks = layer.Webhooks
+			resolved.Settings["webhooks"] = source
+		}
+		if len(layer.Integrations) > 0 {
+			merged.Integrations = layer.Integrations
+			resolved.Settings["integrations"] = source
+		}
+		if layer.Emails.NotifyOn != "" || len(layer.Emails.Recipients) > 0 {
+			merged.Emails = layer.Emails
+			resolved.Settings["emails"] = source
+		}
+		if layer.Script != "" {
+			merged.Script = layer.Script
+			resolved.Settings["script"] = source
+		}
+	}
+	return resolved, nil
+}
+
+func sourceOf(environment Environment) ValueSource {
+	return ValueSource{
+		EnvironmentID:   environment.ID,
+		EnvironmentName: environment.Name,
+		Shared:          environment.TestID == "",
+	}
+}
@@ -0,0 +1,94 @@
+package runscope
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestResolveEnvironment(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleGet(t, "/buckets/1/environments", http.StatusOK, `{"data": [{
+		"id": "shared-1",
+		"name": "Production",
+		"regions": ["us1", "eu1"],
+		"verify_ssl": true,
+		"webhooks": ["https://hooks.example.com"],
+		"script": "log('shared');",
+		"initial_variables": {"baseUrl": "https://api.example.com", "user": "grace"}
+	}]}`)
+	handleGet(t, "/buckets/1/tests/2/environments", http.StatusOK, `{"data": [{
+		"id": "test-env-1",
+		"name": "Production (canary)",
+		"test_id": "2",
+		"parent_environment_id": "shared-1",
+		"regions": ["us2"],
+		"initial_variables": {"baseUrl": "https://canary.example.com"}
+	}]}`)
+
+	resolved, err := client.ResolveEnvironment("1", "2", "test-env-1")
+	if err != nil {
+		t.Fatalf("ResolveEnvironment returned error: %v", err)
+	}
+
+	want := map[string]string{"baseUrl": "https://canary.example.com", "user": "grace"}
+	if !reflect.DeepEqual(resolved.Environment.InitialVariables, want) {
+		t.Errorf("expected variables %v, got %v", want, resolved.Environment.InitialVariables)
+	}
+	if !reflect.DeepEqual(resolved.Environment.Regions, []string{"us2"}) {
+		t.Errorf("expected regions from the test environment, got %v", resolved.Environment.Regions)
+	}
+	if resolved.Environment.Script != "log('shared');" || len(resolved.Environment.Webhooks) != 1 {
+		t.Errorf("expected script and webhooks from the shared environment, got %+v", resolved.Environment)
+	}
+	if resolved.Environment.ID != "test-env-1" || len(resolved.Chain) != 2 {
+		t.Errorf("unexpected environment %s with chain of %d", resolved.Environment.ID, len(resolved.Chain))
+	}
+
+	test := ValueSource{EnvironmentID: "test-env-1", EnvironmentName: "Production (canary)"}
+	shared := ValueSource{EnvironmentID: "shared-1", EnvironmentName: "Production", Shared: true}
+	provenance := map[ValueSource][]ValueSource{
+		test:   {resolved.Variables["baseUrl"], resolved.Settings["regions"], resolved.Settings["verify_ssl"]},
+		shared: {resolved.Variables["user"], resolved.Settings["script"], resolved.Settings["webhooks"]},
+	}
+	for want, sources := range provenance {
+		for _, source := range sources {
+			if source != want {
+				t.Errorf("expected source %+v, got %+v", want, source)
+			}
+		}
+	}
+	if _, ok := resolved.Settings["emails"]; ok {
+		t.Errorf("expected no source for unset emails")
+	}
+}
+
+func TestResolveEnvironmentNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleGet(t, "/buckets/1/environments", http.StatusOK, `{"data": []}`)
+	handleGet(t, "/buckets/1/tests/2/environments", http.StatusOK, `{"data": []}`)
+
+	if _, err := client.ResolveEnvironment("1", "2", "missing"); err == nil {
+		t.Errorf("expected error resolving a missing environment")
+	}
+}
+
+func TestResolveEnvironmentChainErrors(t *testing.T) {
+	orphan := Environment{ID: "a", TestID: "1", ParentEnvironmentID: "missing"}
+	if _, err := ResolveEnvironmentChain(orphan, nil); err == nil {
+		t.Errorf("expected error for a missing parent")
+	}
+
+	shared := []Environment{
+		{ID: "b", ParentEnvironmentID: "c"},
+		{ID: "c", ParentEnvironmentID: "b"},
+	}
+	_, err := ResolveEnvironmentChain(Environment{ID: "a", TestID: "1", ParentEnvironmentID: "b"}, shared)
+	if err == nil {
+		t.Errorf("expected error for an inheritance cycle")
+	}
+}
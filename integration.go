@@ -1,6 +1,9 @@
 package runscope
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // Integration represents a Runscope integration
 type Integration struct {
@@ -12,10 +15,15 @@ type Integration struct {
 
 // ListIntegrations returns all integrations for a given team
 func (client *Client) ListIntegrations(teamID string) ([]Integration, error) {
+	return client.ListIntegrationsWithContext(context.Background(), teamID)
+}
+
+// ListIntegrationsWithContext is the same as ListIntegrations, bound to the supplied context
+func (client *Client) ListIntegrationsWithContext(ctx context.Context, teamID string) ([]Integration, error) {
 	var integrations = []Integration{}
 
 	path := fmt.Sprintf("teams/%s/integrations", teamID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return integrations, err
 	}
@@ -0,0 +1,205 @@
+package runscope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultPageSize is the number of items fetched per request by
+// iterators when no page size is given
+const DefaultPageSize = 50
+
+// Iterator walks through items returned by a paginated Runscope
+// endpoint, fetching pages as they are needed:
+//
+//	it := client.IterateTests(ctx, bucketKey, 0)
+//	for it.Next() {
+//		test := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Stopping before the last item avoids fetching the remaining pages.
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context) ([]T, bool, error)
+	page  []T
+	index int
+	more  bool
+	err   error
+}
+
+// newIterator creates an iterator calling fetch for each page. fetch
+// returns the page and whether more pages may follow.
+func newIterator[T any](ctx context.Context, fetch func(ctx context.Context) ([]T, bool, error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, index: -1, more: true}
+}
+
+// Next advances to the next item, returning false when there are no
+// more items or an error occurred
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.more {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page, it.more, it.err = it.fetch(it.ctx)
+		it.index = 0
+		if it.err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Value returns the current item
+func (it *Iterator[T]) Value() T {
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All returns every remaining item
+func (it *Iterator[T]) All() ([]T, error) {
+	items := []T{}
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+// singlePage returns an iterator over an endpoint which is not paginated
+func singlePage[T any](ctx context.Context, list func(ctx context.Context) ([]T, error)) *Iterator[T] {
+	return newIterator(ctx, func(ctx context.Context) ([]T, bool, error) {
+		items, err := list(ctx)
+		return items, false, err
+	})
+}
+
+// IterateTests returns an iterator over every test in a bucket, fetching
+// pageSize tests per request
+func (client *Client) IterateTests(ctx context.Context, bucketKey string, pageSize int) *Iterator[Test] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	offset := 0
+	return newIterator(ctx, func(ctx context.Context) ([]Test, bool, error) {
+		tests, err := client.ListTestsWithContext(ctx, bucketKey, ListTestOptions{
+			Count:  pageSize,
+			Offset: offset,
+		})
+		offset += pageSize
+		return tests, len(tests) == pageSize, err
+	})
+}
+
+// IterateResults returns an iterator over every result of a test, newest
+// first. Pages of pageSize results (at most 50) are fetched by walking
+// backwards with the "before" filter from the oldest result seen so far.
+func (client *Client) IterateResults(ctx context.Context, bucketKey string, testID string, pageSize int) *Iterator[Result] {
+	return client.iterateResults(ctx, bucketKey, testID, pageSize, nil)
+}
+
+// resultCursorOverlap is added to the oldest start time of a page to form
+// the next "before" cursor. The filter is exclusive, so without the
+// overlap results sharing that start time on the next page would be
+// skipped. Results fetched twice are dropped by their TestRunID.
+const resultCursorOverlap = time.Millisecond
+
+// iterateResults walks results backwards, starting before the given time
+// when it is not nil
+func (client *Client) iterateResults(ctx context.Context, bucketKey string, testID string, pageSize int, before *time.Time) *Iterator[Result] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > 50 {
+		return newIterator(ctx, func(ctx context.Context) ([]Result, bool, error) {
+			return nil, false, errors.New("Result page size has maximal value of 50!")
+		})
+	}
+
+	seen := map[string]bool{}
+	return newIterator(ctx, func(ctx context.Context) ([]Result, bool, error) {
+		results, err := client.FilterResultsWithContext(ctx, bucketKey, testID, int64(pageSize), nil, before)
+		if err != nil {
+			return nil, false, err
+		}
+
+		page := []Result{}
+		var oldest time.Time
+		for _, result := range results {
+			if started := floatToTime(result.StartedAt); oldest.IsZero() || started.Before(oldest) {
+				oldest = started
+			}
+			if seen[result.TestRunID] {
+				continue
+			}
+			seen[result.TestRunID] = true
+			page = append(page, result)
+		}
+
+		more := len(results) == pageSize
+		if more && len(page) == 0 {
+			return nil, false, fmt.Errorf("%d or more results started at %s, use a larger page size", pageSize, oldest)
+		}
+		// never move the cursor past the previous one, which may be the
+		// caller's upper bound
+		cursor := oldest.Add(resultCursorOverlap)
+		if before != nil && cursor.After(*before) {
+			cursor = *before
+		}
+		before = &cursor
+		return page, more, nil
+	})
+}
+
+// IterateBuckets returns an iterator over every bucket in the account
+func (client *Client) IterateBuckets(ctx context.Context) *Iterator[Bucket] {
+	return singlePage(ctx, client.ListBucketsWithContext)
+}
+
+// IterateSteps returns an iterator over every step of a test
+func (client *Client) IterateSteps(ctx context.Context, bucketKey string, testID string) *Iterator[Step] {
+	return singlePage(ctx, func(ctx context.Context) ([]Step, error) {
+		return client.ListStepsWithContext(ctx, bucketKey, testID)
+	})
+}
+
+// IterateSchedules returns an iterator over every schedule of a test
+func (client *Client) IterateSchedules(ctx context.Context, bucketKey string, testID string) *Iterator[Schedule] {
+	return singlePage(ctx, func(ctx context.Context) ([]Schedule, error) {
+		return client.ListSchedulesWithContext(ctx, bucketKey, testID)
+	})
+}
+
+// IterateSharedEnvironments returns an iterator over the shared
+// environments of a bucket
+func (client *Client) IterateSharedEnvironments(ctx context.Context, bucketKey string) *Iterator[Environment] {
+	return singlePage(ctx, func(ctx context.Context) ([]Environment, error) {
+		return client.ListSharedEnvironmentsWithContext(ctx, bucketKey)
+	})
+}
+
+// IterateTestEnvironments returns an iterator over the environments of a test
+func (client *Client) IterateTestEnvironments(ctx context.Context, bucketKey string, testID string) *Iterator[Environment] {
+	return singlePage(ctx, func(ctx context.Context) ([]Environment, error) {
+		return client.ListTestEnvironmentsWithContext(ctx, bucketKey, testID)
+	})
+}
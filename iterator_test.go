@@ -0,0 +1,125 @@
+package runscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// handleTestPages serves total tests from /buckets/1/tests honoring
+// count and offset, and records the offsets requested
+func handleTestPages(t *testing.T, total int) *[]int {
+	offsets := []int{}
+	mux.HandleFunc("/buckets/1/tests", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, offset)
+
+		tests := []Test{}
+		for i := offset; i < total && i < offset+count; i++ {
+			tests = append(tests, Test{ID: strconv.Itoa(i)})
+		}
+		data, _ := json.Marshal(tests)
+		fmt.Fprintf(w, `{"data": %s, "error": null, "meta": {"status": "success"}}`, data)
+	})
+	return &offsets
+}
+
+func TestIterateTests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	offsets := handleTestPages(t, 25)
+
+	tests, err := client.IterateTests(context.Background(), "1", 10).All()
+	if err != nil {
+		t.Fatalf("IterateTests returned error: %v", err)
+	}
+	if len(tests) != 25 || tests[24].ID != "24" {
+		t.Errorf("IterateTests returned %d tests, want 25", len(tests))
+	}
+	testResponseData(t, *offsets, []int{0, 10, 20})
+}
+
+func TestIterateTestsEarlyTermination(t *testing.T) {
+	setup()
+	defer teardown()
+
+	offsets := handleTestPages(t, 100)
+
+	it := client.IterateTests(context.Background(), "1", 10)
+	for i := 0; i < 15 && it.Next(); i++ {
+		if it.Value().ID != strconv.Itoa(i) {
+			t.Errorf("Test %d has ID %s", i, it.Value().ID)
+		}
+	}
+	testResponseData(t, *offsets, []int{0, 10})
+}
+
+func TestIterateTestsError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleGet(t, "/buckets/1/tests", http.StatusInternalServerError, "")
+
+	it := client.IterateTests(context.Background(), "1", 10)
+	if it.Next() {
+		t.Error("Next should return false on error")
+	}
+	if it.Err() == nil {
+		t.Error("Err should return the request error")
+	}
+}
+
+func TestIterateResults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// results 0..11 started at 1000, 999, ... with the page boundary
+	// result returned twice to exercise de-duplication
+	var befores []string
+	mux.HandleFunc("/buckets/1/tests/1/results", func(w http.ResponseWriter, r *http.Request) {
+		before := r.URL.Query().Get("before")
+		befores = append(befores, before)
+
+		limit := 1001.0
+		if before != "" {
+			limit, _ = strconv.ParseFloat(before, 64)
+			limit += 1
+		}
+
+		results := []Result{}
+		for i := 0; i < 12 && len(results) < 5; i++ {
+			started := float64(1000 - i)
+			if started < limit {
+				results = append(results, Result{TestRunID: strconv.Itoa(i), StartedAt: started})
+			}
+		}
+		data, _ := json.Marshal(results)
+		fmt.Fprintf(w, `{"data": %s, "error": null, "meta": {"status": "success"}}`, data)
+	})
+
+	results, err := client.IterateResults(context.Background(), "1", "1", 5).All()
+	if err != nil {
+		t.Fatalf("IterateResults returned error: %v", err)
+	}
+	if len(results) != 12 {
+		t.Fatalf("IterateResults returned %d results, want 12", len(results))
+	}
+	for i, result := range results {
+		if result.TestRunID != strconv.Itoa(i) {
+			t.Errorf("Result %d has ID %s", i, result.TestRunID)
+		}
+	}
+	if befores[0] != "" || befores[1] != "996.001000" {
+		t.Errorf("Result pages requested with before %v", befores)
+	}
+
+	if _, err := client.IterateResults(context.Background(), "1", "1", 100).All(); err == nil {
+		t.Error("IterateResults should reject page sizes above 50")
+	}
+}
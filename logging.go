@@ -0,0 +1,115 @@
+package runscope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Redacted replaces secrets in logged requests and responses
+const Redacted = "[REDACTED]"
+
+// sensitiveFields are JSON keys whose values are never logged
+var sensitiveFields = map[string]bool{
+	"auth_token":      true,
+	"password":        true,
+	"access_token":    true,
+	"token_secret":    true,
+	"consumer_secret": true,
+}
+
+// LoggingMiddleware returns middleware logging the method, path, status,
+// latency and bodies of every request. Successful requests are logged at
+// debug level and failed ones at warn level. The bearer token and
+// sensitive fields such as passwords and bucket auth tokens are redacted.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			start := time.Now()
+			res, err := next(ctx, req)
+
+			token := bearerToken(req.Header)
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", redactString(req.Path, token)),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("request_body", redactBody(req.Body, token)),
+			}
+
+			level := slog.LevelDebug
+			if res != nil {
+				attrs = append(attrs,
+					slog.Int("status", res.StatusCode),
+					slog.String("response_body", redactBody(res.Body, token)),
+				)
+				if res.StatusCode >= 400 {
+					level = slog.LevelWarn
+				}
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", redactString(err.Error(), token)))
+				level = slog.LevelWarn
+			}
+
+			logger.LogAttrs(ctx, level, "runscope request", attrs...)
+			return res, err
+		}
+	}
+}
+
+func bearerToken(header http.Header) string {
+	return strings.TrimPrefix(header.Get("Authorization"), "Bearer ")
+}
+
+// redactString replaces every occurrence of token in s
+func redactString(s string, token string) string {
+	if token == "" {
+		return s
+	}
+	return strings.Replace(s, token, Redacted, -1)
+}
+
+// redactBody returns a JSON body with sensitive fields and the token
+// redacted. Bodies which are not JSON only have the token redacted.
+func redactBody(body []byte, token string) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return redactString(string(body), token)
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return redactString(string(body), token)
+	}
+	return redactString(string(redacted), token)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveFields[key] {
+				if field != nil && field != "" {
+					v[key] = Redacted
+				}
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
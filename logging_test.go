@@ -0,0 +1,82 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handlePost(t, "/buckets/1/tests/1/steps", http.StatusBadRequest, `{"data": null, "error": {"status": 400, "message": "invalid step"}, "meta": {"status": "error"}}`, nil, nil)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(Options{BaseURL: server.URL, Token: "secret-token", Logger: logger})
+
+	_, err := client.NewStep("1", "1", Step{
+		StepType: "request",
+		URL:      "https://yourapihere.com/?token=secret-token",
+		Auth: Auth{
+			AuthType:       "oauth1",
+			Password:       "hunter2",
+			ConsumerSecret: "consumer-shh",
+			TokenSecret:    "token-shh",
+		},
+	})
+	if err == nil {
+		t.Fatal("NewStep should return an error")
+	}
+
+	output := buf.String()
+	for _, secret := range []string{"secret-token", "hunter2", "consumer-shh", "token-shh"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Log output contains secret %q: %s", secret, output)
+		}
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Log output is not a single JSON entry: %v", err)
+	}
+	for key, want := range map[string]interface{}{
+		"level":  "WARN",
+		"method": "POST",
+		"path":   "/buckets/1/tests/1/steps",
+		"status": float64(400),
+	} {
+		if entry[key] != want {
+			t.Errorf("Log entry %s: %v, want %v", key, entry[key], want)
+		}
+	}
+	if _, ok := entry["latency"]; !ok {
+		t.Error("Log entry should contain latency")
+	}
+	if !strings.Contains(entry["response_body"].(string), "invalid step") {
+		t.Errorf("Log entry response_body: %v", entry["response_body"])
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{``, ``},
+		{`not json abc`, `not json [REDACTED]`},
+		{`{"data": [{"name": "Mobile Apps", "auth_token": "7n7n0917"}]}`, `{"data":[{"auth_token":"[REDACTED]","name":"Mobile Apps"}]}`},
+		{`{"auth": {"auth_type": "basic", "username": "abc", "password": ""}}`, `{"auth":{"auth_type":"basic","password":"","username":"[REDACTED]"}}`},
+		{`{"value": 1.50}`, `{"value":1.50}`},
+	}
+
+	for _, test := range tests {
+		if got := redactBody([]byte(test.body), "abc"); got != test.want {
+			t.Errorf("redactBody(%s): %s, want %s", test.body, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package runscope
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+)
+
+// APIRequest is a request to the Runscope API as seen by middleware
+type APIRequest struct {
+	Method string
+	// URL is the absolute URL of the request, Path is the request URI
+	// relative to the host (e.g. /buckets/:key/tests)
+	URL    string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// APIResponse is a response from the Runscope API as seen by middleware
+type APIResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// RoundTripFunc sends a single request to the Runscope API
+type RoundTripFunc func(ctx context.Context, req *APIRequest) (*APIResponse, error)
+
+// Middleware wraps a RoundTripFunc to add behavior such as logging,
+// metrics or header injection to every request made by the client
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds middleware to the client. Middleware is called in the order it
+// was added, once for every attempt of every request. Use must not be
+// called concurrently with requests.
+func (client *Client) Use(middleware ...Middleware) {
+	client.middleware = append(client.middleware, middleware...)
+}
+
+// roundTrip returns the client's middleware chain ending with send
+func (client *Client) roundTrip() RoundTripFunc {
+	next := client.send
+	for i := len(client.middleware) - 1; i >= 0; i-- {
+		next = client.middleware[i](next)
+	}
+	return next
+}
+
+// send performs the HTTP request described by req
+func (client *Client) send(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header = req.Header
+
+	res, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	return &APIResponse{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       body,
+	}, err
+}
@@ -0,0 +1,75 @@
+package runscope
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	responseData := `{"data": {"name": "Sample Test"}, "error": null, "meta": {"status": "success"}}`
+	var header string
+	mux.HandleFunc("/buckets/1/tests", func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Request-Id")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(responseData))
+	})
+
+	var calls []string
+	var seenRequest *APIRequest
+	var seenResponse *APIResponse
+	client.Use(
+		func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+				calls = append(calls, "first")
+				req.Header.Set("X-Request-Id", "abc123")
+				return next(ctx, req)
+			}
+		},
+		func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+				calls = append(calls, "second")
+				res, err := next(ctx, req)
+				seenRequest, seenResponse = req, res
+				return res, err
+			}
+		},
+	)
+
+	_, err := client.NewTest("1", NewTestRequest{Name: "Sample Test"})
+	if err != nil {
+		t.Fatalf("NewTest returned error: %v", err)
+	}
+
+	testResponseData(t, calls, []string{"first", "second"})
+	if header != "abc123" {
+		t.Errorf("X-Request-Id header: %q, want %q", header, "abc123")
+	}
+	if seenRequest.Method != "POST" || seenRequest.Path != "/buckets/1/tests" {
+		t.Errorf("Middleware request: %s %s, want POST /buckets/1/tests", seenRequest.Method, seenRequest.Path)
+	}
+	if string(seenRequest.Body) != `{"name":"Sample Test","description":""}` {
+		t.Errorf("Middleware request body: %s", seenRequest.Body)
+	}
+	if seenResponse.StatusCode != http.StatusCreated || string(seenResponse.Body) != responseData {
+		t.Errorf("Middleware response: %d %s", seenResponse.StatusCode, seenResponse.Body)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			return &APIResponse{StatusCode: http.StatusNotFound}, nil
+		}
+	})
+
+	if _, err := client.GetBucket("1"); !IsNotFound(err) {
+		t.Errorf("GetBucket returned %v, want a not found error", err)
+	}
+}
@@ -0,0 +1,119 @@
+package runscope
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a client-side token bucket limiting how quickly
+// requests are sent to Runscope
+type RateLimit struct {
+	// RequestsPerSecond is the rate at which tokens are added to the bucket
+	RequestsPerSecond float64
+	// Burst is the maximum number of requests sent without waiting.
+	// Defaults to 1.
+	Burst int
+}
+
+// rateLimiter is a token bucket shared by every request made by a client.
+// It can also be paused when Runscope asks the client to back off.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newRateLimiter(limit *RateLimit) *rateLimiter {
+	if limit == nil || limit.RequestsPerSecond <= 0 {
+		return &rateLimiter{}
+	}
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a request may be sent or the context is done
+func (limiter *rateLimiter) wait(ctx context.Context) error {
+	return sleep(ctx, limiter.reserve(time.Now()))
+}
+
+// reserve takes a token from the bucket and returns how long the
+// caller must wait before using it
+func (limiter *rateLimiter) reserve(now time.Time) time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	var delay time.Duration
+	if now.Before(limiter.pausedUntil) {
+		delay = limiter.pausedUntil.Sub(now)
+	}
+	if limiter.rate == 0 {
+		return delay
+	}
+
+	limiter.tokens += now.Sub(limiter.last).Seconds() * limiter.rate
+	if limiter.tokens > limiter.burst {
+		limiter.tokens = limiter.burst
+	}
+	limiter.last = now
+	limiter.tokens--
+
+	if limiter.tokens < 0 {
+		wait := time.Duration(-limiter.tokens / limiter.rate * float64(time.Second))
+		if wait > delay {
+			delay = wait
+		}
+	}
+	return delay
+}
+
+// pause holds back every request until the given duration has passed
+func (limiter *rateLimiter) pause(d time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(limiter.pausedUntil) {
+		limiter.pausedUntil = until
+	}
+}
+
+// parseRetryAfter returns how long Runscope asked the client to wait
+// before retrying, from either the Retry-After header (in seconds or as
+// a HTTP date) or the X-RateLimit-Reset header (a unix timestamp)
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return positive(time.Duration(seconds) * time.Second)
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return positive(date.Sub(now))
+		}
+	}
+	if value := header.Get("X-RateLimit-Reset"); value != "" {
+		if reset, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return positive(time.Unix(reset, 0).Sub(now))
+		}
+	}
+	return 0
+}
+
+func positive(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,104 @@
+package runscope
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2016, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		header http.Header
+		want   time.Duration
+	}{
+		{http.Header{}, 0},
+		{http.Header{"Retry-After": {"30"}}, 30 * time.Second},
+		{http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, time.Minute},
+		{http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}}, 0},
+		{http.Header{"X-Ratelimit-Reset": {fmt.Sprint(now.Add(10 * time.Second).Unix())}}, 10 * time.Second},
+		{http.Header{"Retry-After": {"invalid"}}, 0},
+	}
+
+	for _, test := range tests {
+		if got := parseRetryAfter(test.header, now); got != test.want {
+			t.Errorf("parseRetryAfter(%v): %v, want %v", test.header, got, test.want)
+		}
+	}
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := newRateLimiter(&RateLimit{RequestsPerSecond: 10, Burst: 2})
+	now := limiter.last
+
+	for i, want := range []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond} {
+		if got := limiter.reserve(now); got != want {
+			t.Errorf("reserve %d: %v, want %v", i, got, want)
+		}
+	}
+
+	// after a second the bucket is full again
+	if got := limiter.reserve(now.Add(time.Second)); got != 0 {
+		t.Errorf("reserve after refill: %v, want 0", got)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	limiter := newRateLimiter(nil)
+	for i := 0; i < 100; i++ {
+		if got := limiter.reserve(time.Now()); got != 0 {
+			t.Fatalf("reserve without a rate limit: %v, want 0", got)
+		}
+	}
+}
+
+func TestRetryRateLimited(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"name": "Mobile Apps"}, "error": null, "meta": {"status": "success"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(Options{BaseURL: server.URL, Retry: testRetryPolicy()})
+
+	start := time.Now()
+	bucket, err := client.NewBucket(&NewBucketRequest{Name: "Mobile Apps"})
+	if err != nil {
+		t.Fatalf("NewBucket returned error: %v", err)
+	}
+	if bucket.Name != "Mobile Apps" {
+		t.Errorf("Bucket name: %v, want %v", bucket.Name, "Mobile Apps")
+	}
+	if attempts != 2 {
+		t.Errorf("Attempts: %d, want %d", attempts, 2)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Retry-After was not honored, retried after %v", elapsed)
+	}
+}
+
+func TestRateLimitedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(Options{BaseURL: server.URL})
+	_, err := client.ListBuckets()
+	if !IsRateLimited(err) {
+		t.Fatalf("ListBuckets returned %v, want a rate limited error", err)
+	}
+	if retryAfter := err.(*APIError).RetryAfter; retryAfter != 2*time.Minute {
+		t.Errorf("RetryAfter: %v, want %v", retryAfter, 2*time.Minute)
+	}
+}
@@ -1,5 +1,7 @@
 package runscope
 
+import "context"
+
 // Regions represents multiple Runscope regions
 type Regions struct {
 	Regions []Region `json:"regions"`
@@ -15,9 +17,14 @@ type Region struct {
 
 // ListRegions returns all regions known by Runscope
 func (client *Client) ListRegions() (Regions, error) {
+	return client.ListRegionsWithContext(context.Background())
+}
+
+// ListRegionsWithContext is the same as ListRegions, bound to the supplied context
+func (client *Client) ListRegionsWithContext(ctx context.Context) (Regions, error) {
 	var regions = Regions{}
 
-	content, err := client.Get("regions")
+	content, err := client.GetWithContext(ctx, "regions")
 	if err != nil {
 		return regions, err
 	}
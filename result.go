@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -52,10 +53,15 @@ type Request struct {
 
 // ListResults returns all results for a given test
 func (client *Client) ListResults(bucketKey string, testID string) ([]Result, error) {
+	return client.ListResultsWithContext(context.Background(), bucketKey, testID)
+}
+
+// ListResultsWithContext is the same as ListResults, bound to the supplied context
+func (client *Client) ListResultsWithContext(ctx context.Context, bucketKey string, testID string) ([]Result, error) {
 	var results = []Result{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/results", bucketKey, testID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return results, err
 	}
@@ -66,6 +72,11 @@ func (client *Client) ListResults(bucketKey string, testID string) ([]Result, er
 
 // FilterResults returns results for a given test and supplied conditions
 func (client *Client) FilterResults(bucketKey, testID string, count int64, since, before *time.Time) ([]Result, error) {
+	return client.FilterResultsWithContext(context.Background(), bucketKey, testID, count, since, before)
+}
+
+// FilterResultsWithContext is the same as FilterResults, bound to the supplied context
+func (client *Client) FilterResultsWithContext(ctx context.Context, bucketKey, testID string, count int64, since, before *time.Time) ([]Result, error) {
 	var results = []Result{}
 
 	filterQs, err := client.buildFilterQS(count, since, before)
@@ -74,7 +85,7 @@ func (client *Client) FilterResults(bucketKey, testID string, count int64, since
 	}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/results%s", bucketKey, testID, filterQs)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return results, err
 	}
@@ -85,10 +96,15 @@ func (client *Client) FilterResults(bucketKey, testID string, count int64, since
 
 // GetResult returns a more detail result for a result ID
 func (client *Client) GetResult(bucketKey string, testID string, testRunID string) (Result, error) {
+	return client.GetResultWithContext(context.Background(), bucketKey, testID, testRunID)
+}
+
+// GetResultWithContext is the same as GetResult, bound to the supplied context
+func (client *Client) GetResultWithContext(ctx context.Context, bucketKey string, testID string, testRunID string) (Result, error) {
 	var result = Result{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/results/%s", bucketKey, testID, testRunID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return result, err
 	}
@@ -99,7 +115,53 @@ func (client *Client) GetResult(bucketKey string, testID string, testRunID strin
 
 // GetResultLatest returns the last known result for a given test
 func (client *Client) GetResultLatest(bucketKey string, testID string) (Result, error) {
-	return client.GetResult(bucketKey, testID, "latest")
+	return client.GetResultLatestWithContext(context.Background(), bucketKey, testID)
+}
+
+// GetResultLatestWithContext is the same as GetResultLatest, bound to the supplied context
+func (client *Client) GetResultLatestWithContext(ctx context.Context, bucketKey string, testID string) (Result, error) {
+	return client.GetResultWithContext(ctx, bucketKey, testID, "latest")
+}
+
+// ListAllResults returns every result for a given test started between
+// since and until, newest first. A zero since or until leaves that end
+// of the window open.
+func (client *Client) ListAllResults(bucketKey string, testID string, since time.Time, until time.Time) ([]Result, error) {
+	return client.ListAllResultsWithContext(context.Background(), bucketKey, testID, since, until)
+}
+
+// ListAllResultsWithContext is the same as ListAllResults, bound to the supplied context
+func (client *Client) ListAllResultsWithContext(ctx context.Context, bucketKey string, testID string, since time.Time, until time.Time) ([]Result, error) {
+	var results = []Result{}
+
+	err := client.WalkResults(ctx, bucketKey, testID, since, until, func(result Result) error {
+		results = append(results, result)
+		return nil
+	})
+	return results, err
+}
+
+// WalkResults calls fn for every result of a given test started between
+// since and until, newest first, paging backwards through the results
+// with the "before" filter. Results are de-duplicated by test run ID.
+// Walking stops at the first error returned by fn.
+func (client *Client) WalkResults(ctx context.Context, bucketKey string, testID string, since time.Time, until time.Time, fn func(Result) error) error {
+	var before *time.Time
+	if !until.IsZero() {
+		before = &until
+	}
+
+	it := client.iterateResults(ctx, bucketKey, testID, DefaultPageSize, before)
+	for it.Next() {
+		result := it.Value()
+		if !since.IsZero() && floatToTime(result.StartedAt).Before(since) {
+			return nil
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return it.Err()
 }
 
 // Builds filter for list results (count maximum is 50 and since/before are exclusive!)
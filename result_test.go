@@ -1,7 +1,11 @@
 package runscope
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -483,3 +487,121 @@ func TestGetResultLatest(t *testing.T) {
 	}
 	testResponseData(t, result, want)
 }
+
+// handleResultHistory serves total results started one minute apart,
+// newest first, honoring the count and before filters
+func handleResultHistory(t *testing.T, newest time.Time, total int) *int {
+	requests := 0
+	mux.HandleFunc("/buckets/1/tests/1/results", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		before := math.Inf(1)
+		if value := r.URL.Query().Get("before"); value != "" {
+			before, _ = strconv.ParseFloat(value, 64)
+		}
+
+		results := []Result{}
+		for i := 0; i < total && len(results) < count; i++ {
+			started := unixTimestampToFloat(newest.Add(-time.Duration(i) * time.Minute))
+			if started < before {
+				results = append(results, Result{TestRunID: strconv.Itoa(i), StartedAt: started})
+			}
+		}
+		data, _ := json.Marshal(results)
+		fmt.Fprintf(w, `{"data": %s, "error": null, "meta": {"status": "success"}}`, data)
+	})
+	return &requests
+}
+
+func TestListAllResults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	newest := time.Date(2016, 1, 2, 15, 0, 0, 0, time.UTC)
+	requests := handleResultHistory(t, newest, 200)
+
+	since := newest.Add(-150 * time.Minute)
+	until := newest.Add(-10*time.Minute + time.Second)
+	results, err := client.ListAllResults("1", "1", since, until)
+	if err != nil {
+		t.Fatalf("ListAllResults returned error: %v", err)
+	}
+
+	// results 10 through 150 fall in the window
+	if len(results) != 141 {
+		t.Fatalf("ListAllResults returned %d results, want 141", len(results))
+	}
+	if results[0].TestRunID != "10" || results[140].TestRunID != "150" {
+		t.Errorf("ListAllResults returned results %s to %s, want 10 to 150", results[0].TestRunID, results[140].TestRunID)
+	}
+	if *requests != 3 {
+		t.Errorf("Requests: %d, want %d", *requests, 3)
+	}
+
+	all, err := client.ListAllResults("1", "1", time.Time{}, time.Time{})
+	if err != nil || len(all) != 200 {
+		t.Errorf("ListAllResults without bounds returned %d results, %v", len(all), err)
+	}
+}
+
+func TestWalkResultsStop(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleResultHistory(t, time.Now(), 100)
+
+	stop := errors.New("stop")
+	walked := 0
+	err := client.WalkResults(context.Background(), "1", "1", time.Time{}, time.Time{}, func(result Result) error {
+		walked++
+		if walked == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("WalkResults returned %v, want %v", err, stop)
+	}
+	if walked != 3 {
+		t.Errorf("WalkResults walked %d results, want 3", walked)
+	}
+}
+
+func TestListAllResultsTiedStartTimes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// the 3 oldest of 52 results, which straddle the first page boundary,
+	// started at the same time
+	newest := time.Date(2016, 1, 2, 15, 0, 0, 0, time.UTC)
+	started := func(i int) float64 {
+		if i > 49 {
+			i = 49
+		}
+		return unixTimestampToFloat(newest.Add(-time.Duration(i) * time.Minute))
+	}
+	mux.HandleFunc("/buckets/1/tests/1/results", func(w http.ResponseWriter, r *http.Request) {
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		before := math.Inf(1)
+		if value := r.URL.Query().Get("before"); value != "" {
+			before, _ = strconv.ParseFloat(value, 64)
+		}
+
+		results := []Result{}
+		for i := 0; i < 52 && len(results) < count; i++ {
+			if started(i) < before {
+				results = append(results, Result{TestRunID: strconv.Itoa(i), StartedAt: started(i)})
+			}
+		}
+		data, _ := json.Marshal(results)
+		fmt.Fprintf(w, `{"data": %s, "error": null, "meta": {"status": "success"}}`, data)
+	})
+
+	results, err := client.ListAllResults("1", "1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("ListAllResults returned error: %v", err)
+	}
+	if len(results) != 52 {
+		t.Errorf("ListAllResults returned %d results, want 52", len(results))
+	}
+}
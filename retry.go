@@ -0,0 +1,137 @@
+package runscope
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Delays grow
+// exponentially from BaseDelay up to MaxDelay between attempts.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request,
+	// including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Jitter is the fraction (0 to 1) of each delay that is randomized
+	Jitter float64
+	// RetryableMethods lists the HTTP methods that may be retried. POST
+	// is only retried when it is listed here as well, since creating
+	// resources is not idempotent. Rate limited (429) requests were never
+	// processed and are retried regardless of their method.
+	RetryableMethods []string
+	// RetryableStatuses lists the HTTP status codes that trigger a retry.
+	// Connection errors are always retried for retryable methods.
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy returns a policy retrying idempotent requests on
+// gateway errors and connection failures
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:       4,
+		BaseDelay:         250 * time.Millisecond,
+		MaxDelay:          5 * time.Second,
+		Jitter:            0.5,
+		RetryableMethods:  []string{"GET", "PUT", "DELETE"},
+		RetryableStatuses: []int{429, 502, 503, 504},
+	}
+}
+
+func (policy *RetryPolicy) attempts() int {
+	if policy == nil || policy.MaxAttempts < 1 {
+		return 1
+	}
+	return policy.MaxAttempts
+}
+
+func (policy *RetryPolicy) retryableMethod(method string) bool {
+	for _, m := range policy.RetryableMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldRetry reports whether a request should be attempted again given
+// the status code or error of the previous attempt
+func (policy *RetryPolicy) shouldRetry(ctx context.Context, method string, code int, err error) bool {
+	if policy == nil || ctx.Err() != nil {
+		return false
+	}
+	if !policy.retryableMethod(method) && code != http.StatusTooManyRequests {
+		return false
+	}
+	if err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			return true
+		}
+	}
+	for _, c := range policy.RetryableStatuses {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry, starting at 1. The
+// delay doubles with each retry and is only capped when MaxDelay is set.
+func (policy *RetryPolicy) backoff(retry int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 1; i < retry && delay < math.MaxInt64/2; i++ {
+		if policy.MaxDelay > 0 && delay >= policy.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if policy.Jitter > 0 {
+		delay -= time.Duration(policy.Jitter * rand.Float64() * float64(delay))
+	}
+	return delay
+}
+
+// delay returns the time to wait before the given retry, honoring any
+// Retry-After sent by Runscope with the previous error
+func (policy *RetryPolicy) delay(retry int, err error) time.Duration {
+	delay := policy.backoff(retry)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return delay
+}
+
+// sleep waits for the given duration or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// statusCode returns the status code of a response, or zero if there
+// was no response
+func statusCode(res *APIResponse) int {
+	if res == nil {
+		return 0
+	}
+	return res.StatusCode
+}
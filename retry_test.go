@@ -0,0 +1,151 @@
+package runscope
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newRetryServer(failures int, code int) (*httptest.Server, *int) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= failures {
+			w.WriteHeader(code)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"name": "Grace Hopper"}, "error": null, "meta": {"status": "success"}}`)
+	}))
+	return server, &attempts
+}
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetryTransientStatus(t *testing.T) {
+	server, attempts := newRetryServer(2, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	client := NewClient(Options{BaseURL: server.URL, Retry: testRetryPolicy()})
+	account, err := client.GetAccount()
+	if err != nil {
+		t.Fatalf("GetAccount returned error: %v", err)
+	}
+	if account.Name != "Grace Hopper" {
+		t.Errorf("Account name: %v, want %v", account.Name, "Grace Hopper")
+	}
+	if *attempts != 3 {
+		t.Errorf("Attempts: %d, want %d", *attempts, 3)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server, attempts := newRetryServer(10, http.StatusBadGateway)
+	defer server.Close()
+
+	client := NewClient(Options{BaseURL: server.URL, Retry: testRetryPolicy()})
+	_, err := client.GetAccount()
+	if err == nil {
+		t.Fatal("GetAccount should return an error after exhausting retries")
+	}
+	if *attempts != 4 {
+		t.Errorf("Attempts: %d, want %d", *attempts, 4)
+	}
+}
+
+func TestRetryNonRetryableStatus(t *testing.T) {
+	server, attempts := newRetryServer(1, http.StatusNotFound)
+	defer server.Close()
+
+	client := NewClient(Options{BaseURL: server.URL, Retry: testRetryPolicy()})
+	if _, err := client.GetAccount(); !IsNotFound(err) {
+		t.Errorf("GetAccount returned %v, want a not found error", err)
+	}
+	if *attempts != 1 {
+		t.Errorf("Attempts: %d, want %d", *attempts, 1)
+	}
+}
+
+func TestRetryPost(t *testing.T) {
+	server, attempts := newRetryServer(1, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	client := NewClient(Options{BaseURL: server.URL, Retry: testRetryPolicy()})
+	if _, err := client.Post("buckets", nil); err == nil {
+		t.Error("POST should not be retried by the default policy")
+	}
+	if *attempts != 1 {
+		t.Errorf("Attempts: %d, want %d", *attempts, 1)
+	}
+
+	policy := testRetryPolicy()
+	policy.RetryableMethods = append(policy.RetryableMethods, "POST")
+	client = NewClient(Options{BaseURL: server.URL, Retry: policy})
+	*attempts = 0
+	if _, err := client.Post("buckets", nil); err != nil {
+		t.Errorf("POST returned error: %v", err)
+	}
+	if *attempts != 2 {
+		t.Errorf("Attempts: %d, want %d", *attempts, 2)
+	}
+}
+
+func TestRetryDisabled(t *testing.T) {
+	server, attempts := newRetryServer(1, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	client := NewClient(Options{BaseURL: server.URL})
+	if _, err := client.GetAccount(); err == nil {
+		t.Error("GetAccount should not be retried without a retry policy")
+	}
+	if *attempts != 1 {
+		t.Errorf("Attempts: %d, want %d", *attempts, 1)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+	}
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		if got := policy.backoff(retry); got != want {
+			t.Errorf("backoff(%d): %v, want %v", retry, got, want)
+		}
+	}
+
+	uncapped := &RetryPolicy{BaseDelay: 100 * time.Millisecond}
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		5: 1600 * time.Millisecond,
+		8: 12800 * time.Millisecond,
+	} {
+		if got := uncapped.backoff(retry); got != want {
+			t.Errorf("backoff(%d) without MaxDelay: %v, want %v", retry, got, want)
+		}
+	}
+	if got := uncapped.backoff(200); got <= 0 {
+		t.Errorf("backoff(200) without MaxDelay: %v, want a positive delay", got)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("backoff(1) with jitter: %v, want between 50ms and 100ms", got)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package runscope
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LocalRegion is the region reported for test runs made by a Runner
+const LocalRegion = "local"
+
+// Runner runs the steps of a test locally, e.g. against a service on
+// localhost, instead of on Runscope
+type Runner struct {
+	// HTTPClient sends the requests of the test's steps. When nil, a
+	// client with DefaultTimeout is used.
+	HTTPClient *http.Client
+	// Environment supplies the initial variables of the run
+	Environment Environment
+}
+
+// NewRunner creates a Runner for the given environment
+func NewRunner(environment Environment) *Runner {
+	return &Runner{Environment: environment}
+}
+
+// Run runs the steps of a test in order and returns a Result shaped like
+// the results of Runscope test runs. Variables extracted by a step are
+// available to the steps after it. Subtest and Ghost Inspector steps
+// cannot be run locally and return an error.
+func (runner *Runner) Run(ctx context.Context, test Test) (Result, error) {
+	result := Result{
+		Result:          "pass",
+		Region:          LocalRegion,
+		TestID:          test.ID,
+		TestRunID:       newUUID(),
+		EnvironmentID:   runner.Environment.ID,
+		EnvironmentName: runner.Environment.Name,
+		StartedAt:       unixTimestampToFloat(time.Now()),
+		Requests:        []Request{},
+	}
+
+	variables := map[string]string{}
+	template := NewEnvironmentTemplate(runner.Environment).With(variables)
+
+	err := runner.runSteps(ctx, runner.httpClient(), test.Steps, template, variables, &result)
+	result.FinishedAt = unixTimestampToFloat(time.Now())
+	return result, err
+}
+
+func (runner *Runner) httpClient() *http.Client {
+	client := runner.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if runner.Environment.PreserveCookies && client.Jar == nil {
+		jar, _ := cookiejar.New(nil)
+		copied := *client
+		copied.Jar = jar
+		client = &copied
+	}
+	return client
+}
+
+func (runner *Runner) runSteps(ctx context.Context, client *http.Client, steps []Step, template *Template, variables map[string]string, result *Result) error {
+	for _, step := range steps {
+		switch step.StepType {
+		case StepTypeRequest, "":
+			rendered, _ := template.RenderStep(step)
+			request := runStep(ctx, client, rendered)
+			for _, variable := range request.Variables {
+				if variable.Result == "pass" {
+					variables[variable.Name] = stringify(variable.Value)
+				}
+			}
+			result.addRequest(request)
+		case StepTypePause:
+			if err := sleep(ctx, time.Duration(step.Duration)*time.Second); err != nil {
+				return err
+			}
+		case StepTypeCondition:
+			rendered, _ := template.RenderStep(step)
+			passed, err := compare(Comparison(step.Comparison), rendered.LeftValue, rendered.RightValue)
+			if err != nil {
+				return err
+			}
+			if passed {
+				if err := runner.runSteps(ctx, client, step.Steps, template, variables, result); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("Step type %q cannot be run locally", step.StepType)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRequest records the outcome of a request in the result's totals
+func (result *Result) addRequest(request Request) {
+	result.Requests = append(result.Requests, request)
+	result.RequestsExecuted++
+	result.AssertionsDefined += request.AssertionsDefined
+	result.AssertionsFailed += request.AssertionsFailed
+	result.AssertionsPassed += request.AssertionsPassed
+	result.ScriptsDefined += request.ScriptsDefined
+	result.ScriptsFailed += request.ScriptsFailed
+	result.ScriptsPassed += request.ScriptsPassed
+	result.VariablesDefined += request.VariablesDefined
+	result.VariablesFailed += request.VariablesFailed
+	result.VariablesPassed += request.VariablesPassed
+	if request.Result != "pass" {
+		result.Result = "fail"
+	}
+}
+
+// runStep sends the request of a step and evaluates its response. A
+// request that could not be sent fails every assertion of the step.
+func runStep(ctx context.Context, client *http.Client, step Step) Request {
+	response, err := sendStep(ctx, client, step)
+	if err == nil {
+		return EvaluateStep(step, response)
+	}
+
+	request := Request{
+		Result:            "fail",
+		URL:               step.URL,
+		Method:            step.Method,
+		AssertionsDefined: len(step.Assertions),
+		AssertionsFailed:  len(step.Assertions),
+		VariablesDefined:  len(step.Variables),
+		VariablesFailed:   len(step.Variables),
+		ScriptsDefined:    len(step.Scripts),
+		Assertions:        []Assertion{},
+		Variables:         []Variable{},
+		Scripts:           []Script{},
+	}
+	for _, assertion := range step.Assertions {
+		assertion.Result = "fail"
+		assertion.TargetValue = assertion.Value
+		assertion.Error = err.Error()
+		request.Assertions = append(request.Assertions, assertion)
+	}
+	for _, variable := range step.Variables {
+		variable.Result = "fail"
+		variable.Error = err.Error()
+		request.Variables = append(request.Variables, variable)
+	}
+	return request
+}
+
+func sendStep(ctx context.Context, client *http.Client, step Step) (StepResponse, error) {
+	var body io.Reader
+	contentType := ""
+	if step.Body != "" {
+		body = strings.NewReader(step.Body)
+	} else if len(step.Form) > 0 {
+		body = strings.NewReader(url.Values(step.Form).Encode())
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	method := step.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, step.URL, body)
+	if err != nil {
+		return StepResponse{}, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, values := range step.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if step.Auth.AuthType == "basic" {
+		req.SetBasicAuth(step.Auth.Username, step.Auth.Password)
+	}
+
+	start := time.Now()
+	res, err := client.Do(req)
+	if err != nil {
+		return StepResponse{}, err
+	}
+	return NewStepResponse(res, time.Since(start))
+}
@@ -0,0 +1,131 @@
+package runscope
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestRunnerRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); user != "grace" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"token": "abc", "user": {"id": 7}}`)
+	})
+	mux.HandleFunc("/users/7", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"id": 7, "note": %q}`, body)
+	})
+	mux.HandleFunc("/skipped", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Condition should have skipped the request")
+	})
+
+	test := Test{
+		ID: "test-1",
+		Steps: []Step{
+			{
+				StepType:   StepTypeRequest,
+				Method:     "POST",
+				URL:        "{{baseUrl}}/login",
+				Auth:       Auth{AuthType: "basic", Username: "{{user}}", Password: "secret"},
+				Assertions: []Assertion{AssertStatus().Equals(200)},
+				Variables: []Variable{
+					VariableFromJSON("token", "token"),
+					VariableFromJSON("user_id", "user.id"),
+				},
+			},
+			{StepType: StepTypePause, Duration: 0},
+			{
+				StepType:   StepTypeCondition,
+				LeftValue:  "{{user_id}}",
+				Comparison: string(ComparisonEqualNumber),
+				RightValue: "7",
+				Steps: []Step{{
+					StepType: StepTypeRequest,
+					Method:   "PUT",
+					URL:      "{{baseUrl}}/users/{{user_id}}",
+					Headers:  map[string][]string{"Authorization": {"Bearer {{token}}"}},
+					Body:     "hello {{user}}",
+					Assertions: []Assertion{
+						AssertStatus().Equals(200),
+						AssertJSON("note").Equals("hello {{user}}"),
+					},
+				}},
+			},
+			{
+				StepType:   StepTypeCondition,
+				LeftValue:  "{{user_id}}",
+				Comparison: string(ComparisonEqual),
+				RightValue: "8",
+				Steps:      []Step{{StepType: StepTypeRequest, URL: "{{baseUrl}}/skipped"}},
+			},
+		},
+	}
+
+	runner := NewRunner(Environment{
+		ID:               "env-1",
+		Name:             "Local",
+		InitialVariables: map[string]string{"baseUrl": server.URL, "user": "grace"},
+	})
+	result, err := runner.Run(context.Background(), test)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if result.Result != "pass" {
+		t.Errorf("expected run to pass, got %+v", result)
+	}
+	if result.RequestsExecuted != 2 || len(result.Requests) != 2 {
+		t.Errorf("expected 2 requests executed, got %d", result.RequestsExecuted)
+	}
+	if result.AssertionsPassed != 3 || result.VariablesPassed != 2 {
+		t.Errorf("expected 3 assertions and 2 variables passed, got %d and %d", result.AssertionsPassed, result.VariablesPassed)
+	}
+	if want := server.URL + "/users/7"; result.Requests[1].URL != want {
+		t.Errorf("expected substituted URL %s, got %s", want, result.Requests[1].URL)
+	}
+	if result.TestID != "test-1" || result.EnvironmentID != "env-1" || result.Region != LocalRegion || result.TestRunID == "" {
+		t.Errorf("unexpected result metadata: %+v", result)
+	}
+	if result.FinishedAt < result.StartedAt {
+		t.Errorf("expected finished_at after started_at")
+	}
+}
+
+func TestRunnerRequestError(t *testing.T) {
+	test := Test{Steps: []Step{{
+		StepType:   StepTypeRequest,
+		URL:        "http://127.0.0.1:0/unreachable",
+		Assertions: []Assertion{AssertStatus().Equals(200)},
+		Variables:  []Variable{VariableFromStatus("status")},
+	}}}
+
+	result, err := NewRunner(Environment{}).Run(context.Background(), test)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if result.Result != "fail" || result.AssertionsFailed != 1 || result.VariablesFailed != 1 {
+		t.Errorf("expected unreachable request to fail, got %+v", result)
+	}
+	if result.Requests[0].Assertions[0].Error == "" {
+		t.Errorf("expected assertion to record the request error")
+	}
+}
+
+func TestRunnerUnsupportedStep(t *testing.T) {
+	test := Test{Steps: []Step{{StepType: StepTypeSubtest, TestID: "other"}}}
+	if _, err := NewRunner(Environment{}).Run(context.Background(), test); err == nil {
+		t.Errorf("expected error running a subtest step locally")
+	}
+}
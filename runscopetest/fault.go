@@ -0,0 +1,198 @@
+package runscopetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	runscope "github.com/nextrevision/go-runscope"
+)
+
+// Fault describes how the fake server misbehaves for a request. Faults
+// are injected per route with InjectFault and consumed in order.
+type Fault struct {
+	// Latency delays the response, after which the remaining fields
+	// apply. A fault with only Latency set serves the normal response.
+	Latency time.Duration
+	// StatusCode responds with an error envelope and this status
+	// instead of handling the request
+	StatusCode int
+	// RetryAfter sets the Retry-After header, in whole seconds
+	RetryAfter time.Duration
+	// ErrorMessage responds with 200 and an error envelope carrying
+	// this message instead of handling the request
+	ErrorMessage string
+	// Body responds with 200 and this raw body instead of handling the
+	// request, e.g. to serve malformed JSON
+	Body string
+	// Truncate handles the request but only sends the first half of
+	// the response body
+	Truncate bool
+	// Drop closes the connection without sending a response
+	Drop bool
+	// Times is the number of requests the fault applies to. Defaults to 1.
+	Times int
+}
+
+// Repeat returns a copy of the fault applying to n requests
+func (f Fault) Repeat(n int) Fault {
+	f.Times = n
+	return f
+}
+
+// Latency returns a fault delaying the normal response by d
+func Latency(d time.Duration) Fault {
+	return Fault{Latency: d}
+}
+
+// Status returns a fault responding with the given status code
+func Status(code int) Fault {
+	return Fault{StatusCode: code}
+}
+
+// RateLimited returns a fault responding with 429 and a Retry-After header
+func RateLimited(retryAfter time.Duration) Fault {
+	return Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfter}
+}
+
+// ErrorEnvelope returns a fault responding with 200 and an error envelope
+func ErrorEnvelope(message string) Fault {
+	return Fault{ErrorMessage: message}
+}
+
+// Malformed returns a fault responding with a body which is not valid JSON
+func Malformed() Fault {
+	return Fault{Body: `{"data": {"name": `}
+}
+
+// Truncated returns a fault cutting the normal response body in half
+func Truncated() Fault {
+	return Fault{Truncate: true}
+}
+
+// Dropped returns a fault closing the connection without a response
+func Dropped() Fault {
+	return Fault{Drop: true}
+}
+
+type faultRule struct {
+	method   string
+	segments []string
+	faults   []Fault
+}
+
+// InjectFault scripts faults for requests matching method and path. An
+// empty method matches any method and "*" matches any single path
+// segment, e.g. InjectFault("GET", "buckets/*/tests", Status(503).Repeat(2)).
+// Faults are applied in order and the route behaves normally once every
+// fault has been consumed.
+func (s *Server) InjectFault(method string, path string, faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &faultRule{
+		method:   method,
+		segments: strings.Split(strings.Trim(path, "/"), "/"),
+		faults:   faults,
+	})
+}
+
+// ClearFaults removes every injected fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the number of requests received for method and path,
+// matched the same way as InjectFault, including faulted requests
+func (s *Server) Requests(method string, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule := faultRule{method: method, segments: strings.Split(strings.Trim(path, "/"), "/")}
+	count := 0
+	for _, request := range s.requests {
+		parts := strings.SplitN(request, " ", 2)
+		if rule.matches(route{method: parts[0], segments: strings.Split(parts[1], "/")}) {
+			count++
+		}
+	}
+	return count
+}
+
+// nextFault records the request and consumes the next fault for its route
+func (s *Server) nextFault(rt route) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, rt.method+" "+strings.Join(rt.segments, "/"))
+
+	for _, rule := range s.faults {
+		if !rule.matches(rt) || len(rule.faults) == 0 {
+			continue
+		}
+
+		fault := rule.faults[0]
+		if fault.Times <= 1 {
+			rule.faults = rule.faults[1:]
+		} else {
+			rule.faults[0].Times--
+		}
+		return fault, true
+	}
+	return Fault{}, false
+}
+
+func (rule *faultRule) matches(rt route) bool {
+	if rule.method != "" && rule.method != rt.method {
+		return false
+	}
+	return rt.matchAny(rule.segments...)
+}
+
+func (s *Server) applyFault(w http.ResponseWriter, r *http.Request, rt route, fault Fault) {
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	switch {
+	case fault.Drop:
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	case fault.StatusCode != 0:
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(fault.RetryAfter/time.Second)))
+		}
+		writeError(w, fault.StatusCode, http.StatusText(fault.StatusCode))
+	case fault.ErrorMessage != "":
+		w.Header().Set("Content-Type", "application/json")
+		writeEnvelope(w, http.StatusOK, nil, &runscope.Error{Status: http.StatusBadRequest, Message: fault.ErrorMessage}, "error")
+	case fault.Body != "":
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fault.Body))
+	case fault.Truncate:
+		recorder := httptest.NewRecorder()
+		s.respond(recorder, rt)
+		body := recorder.Body.Bytes()
+		for key, values := range recorder.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(recorder.Code)
+		w.Write(body[:len(body)/2])
+	default:
+		s.respond(w, rt)
+	}
+}
@@ -0,0 +1,115 @@
+package runscopetest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	runscope "github.com/nextrevision/go-runscope"
+)
+
+func newFaultClient(server *Server) *runscope.Client {
+	policy := runscope.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	return runscope.NewClient(runscope.Options{BaseURL: server.URL, Retry: policy})
+}
+
+func TestFaultStatusBurst(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newFaultClient(server)
+
+	server.InjectFault("GET", "account", Status(http.StatusServiceUnavailable).Repeat(2))
+	if _, err := client.GetAccount(); err != nil {
+		t.Fatalf("GetAccount returned error: %v", err)
+	}
+	if requests := server.Requests("GET", "account"); requests != 3 {
+		t.Errorf("Requests: %d, want %d", requests, 3)
+	}
+
+	server.InjectFault("", "account", Status(http.StatusBadGateway).Repeat(10))
+	if _, err := client.GetAccount(); err == nil {
+		t.Error("GetAccount should fail once retries are exhausted")
+	}
+	server.ClearFaults()
+	if _, err := client.GetAccount(); err != nil {
+		t.Errorf("GetAccount after ClearFaults returned error: %v", err)
+	}
+}
+
+func TestFaultRateLimited(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.InjectFault("GET", "buckets", RateLimited(time.Minute))
+	_, err := server.Client().ListBuckets()
+	if !runscope.IsRateLimited(err) {
+		t.Fatalf("ListBuckets returned %v, want a rate limited error", err)
+	}
+	if retryAfter := err.(*runscope.APIError).RetryAfter; retryAfter != time.Minute {
+		t.Errorf("RetryAfter: %v, want %v", retryAfter, time.Minute)
+	}
+}
+
+func TestFaultResponses(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	bucket, _ := client.NewBucket(&runscope.NewBucketRequest{Name: "Mobile Apps"})
+	server.InjectFault("GET", "buckets/*", ErrorEnvelope("bucket is locked"), Malformed(), Truncated())
+
+	_, err := client.GetBucket(bucket.Key)
+	if apiErr, ok := err.(*runscope.APIError); !ok || apiErr.Message != "bucket is locked" {
+		t.Errorf("GetBucket with error envelope returned %v", err)
+	}
+	if _, err := client.GetBucket(bucket.Key); err == nil {
+		t.Error("GetBucket with malformed JSON should fail")
+	}
+	if _, err := client.GetBucket(bucket.Key); err == nil {
+		t.Error("GetBucket with truncated JSON should fail")
+	}
+	if _, err := client.GetBucket(bucket.Key); err != nil {
+		t.Errorf("GetBucket after faults returned error: %v", err)
+	}
+}
+
+func TestFaultDropped(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newFaultClient(server)
+
+	server.InjectFault("GET", "regions", Dropped())
+	if _, err := client.ListRegions(); err != nil {
+		t.Errorf("ListRegions should be retried after a dropped connection: %v", err)
+	}
+
+	server.InjectFault("GET", "regions", Dropped())
+	if _, err := server.Client().ListRegions(); err == nil {
+		t.Error("ListRegions without retries should fail on a dropped connection")
+	}
+}
+
+func TestFaultLatency(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	server.InjectFault("GET", "account", Latency(time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetAccountWithContext(ctx); err == nil {
+		t.Error("GetAccountWithContext should time out")
+	}
+
+	server.InjectFault("GET", "account", Latency(10*time.Millisecond))
+	start := time.Now()
+	if _, err := client.GetAccount(); err != nil {
+		t.Errorf("GetAccount returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("GetAccount returned after %v, want at least 10ms", elapsed)
+	}
+}
@@ -0,0 +1,941 @@
+// Package runscopetest provides an in-memory fake of the Runscope API for
+// testing code built on the runscope client.
+//
+// The fake keeps real state: buckets, tests, steps, environments,
+// schedules and results created through the client can be read back,
+// updated and deleted, and triggering a test records a new result.
+// Faults such as latency, rate limiting, 5xx bursts, malformed responses
+// and dropped connections can be scripted per route with InjectFault.
+package runscopetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	runscope "github.com/nextrevision/go-runscope"
+)
+
+// Server is a fake Runscope API backed by in-memory state
+type Server struct {
+	*httptest.Server
+
+	// RunResult is the result recorded for triggered test runs.
+	// Defaults to "pass".
+	RunResult string
+
+	mu           sync.Mutex
+	faults       []*faultRule
+	requests     []string
+	account      runscope.Account
+	regions      []runscope.Region
+	people       map[string][]runscope.Person
+	integrations map[string][]runscope.Integration
+	buckets      []*bucket
+}
+
+type bucket struct {
+	runscope.Bucket
+	triggerID    string
+	environments []runscope.Environment
+	tests        []*test
+}
+
+type test struct {
+	runscope.Test
+	triggerID string
+	results   []runscope.Result
+}
+
+// route is a parsed request path, e.g. buckets/:key/tests/:id/steps/:id
+type route struct {
+	method   string
+	segments []string
+	query    map[string][]string
+	body     []byte
+}
+
+// NewServer starts a fake Runscope API with an account, a single team
+// and the default regions. Call Close when done.
+func NewServer() *Server {
+	team := runscope.Team{Name: "Personal Team", ID: newID(), UUID: newID()}
+	s := &Server{
+		RunResult: "pass",
+		account: runscope.Account{
+			Name:  "Grace Hopper",
+			Email: "grace@example.com",
+			ID:    newID(),
+			UUID:  newID(),
+			Teams: []runscope.Team{team},
+		},
+		regions: []runscope.Region{
+			{RegionCode: "us1", Location: "US Virginia", ServiceProvider: "Amazon Web Services", Hostname: "us1.runscope.net"},
+			{RegionCode: "eu1", Location: "EU Ireland", ServiceProvider: "Amazon Web Services", Hostname: "eu1.runscope.net"},
+		},
+		people:       map[string][]runscope.Person{},
+		integrations: map[string][]runscope.Integration{},
+	}
+	s.account.CreatedAt = now()
+	s.people[team.ID] = []runscope.Person{{Name: s.account.Name, Email: s.account.Email, ID: s.account.ID, UUID: s.account.UUID}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a runscope client configured to use the fake server
+func (s *Server) Client() *runscope.Client {
+	return runscope.NewClient(runscope.Options{BaseURL: s.URL, Token: "runscopetest"})
+}
+
+// Account returns the account served by the fake
+func (s *Server) Account() runscope.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.account
+}
+
+// AddTeam adds a team to the account and returns it
+func (s *Server) AddTeam(name string) runscope.Team {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team := runscope.Team{Name: name, ID: newID(), UUID: newID()}
+	s.account.Teams = append(s.account.Teams, team)
+	return team
+}
+
+// AddPerson adds a person to a team
+func (s *Server) AddPerson(teamID string, person runscope.Person) runscope.Person {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if person.ID == "" {
+		person.ID = newID()
+	}
+	s.people[teamID] = append(s.people[teamID], person)
+	return person
+}
+
+// AddIntegration adds an integration to a team
+func (s *Server) AddIntegration(teamID string, integration runscope.Integration) runscope.Integration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if integration.ID == "" {
+		integration.ID = newID()
+	}
+	s.integrations[teamID] = append(s.integrations[teamID], integration)
+	return integration
+}
+
+// AddResult records a result for a test, as if it had been run
+func (s *Server) AddResult(bucketKey string, testID string, result runscope.Result) (runscope.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.findTest(bucketKey, testID)
+	if t == nil {
+		return result, fmt.Errorf("test %s not found in bucket %s", testID, bucketKey)
+	}
+	if result.TestRunID == "" {
+		result.TestRunID = newID()
+	}
+	result.TestID = testID
+	result.BucketKey = bucketKey
+	t.results = append(t.results, result)
+	return result, nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rt := route{
+		method:   r.Method,
+		segments: strings.Split(strings.Trim(r.URL.Path, "/"), "/"),
+		query:    r.URL.Query(),
+		body:     body,
+	}
+
+	if fault, found := s.nextFault(rt); found {
+		s.applyFault(w, r, rt, fault)
+		return
+	}
+	s.respond(w, rt)
+}
+
+// respond writes the response for a request to the in-memory API
+func (s *Server) respond(w http.ResponseWriter, rt route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, data, err := s.dispatch(rt)
+	if err != nil {
+		writeError(w, code, err.Error())
+		return
+	}
+	writeData(w, code, data)
+}
+
+// dispatch routes a request to its handler and returns the status code
+// and data to respond with
+func (s *Server) dispatch(rt route) (int, interface{}, error) {
+	switch {
+	case rt.match("GET", "account"):
+		return ok(s.account)
+	case rt.match("GET", "regions"):
+		return ok(runscope.Regions{Regions: s.regions})
+	case rt.match("GET", "teams", "*", "people"):
+		return ok(nonNil(s.people[rt.segments[1]]))
+	case rt.match("GET", "teams", "*", "integrations"):
+		return ok(nonNil(s.integrations[rt.segments[1]]))
+	case rt.matchPrefix("radar"):
+		return s.trigger(rt)
+	case rt.matchPrefix("buckets"):
+		return s.dispatchBuckets(rt)
+	}
+	return notFound("route")
+}
+
+func (s *Server) dispatchBuckets(rt route) (int, interface{}, error) {
+	if rt.match("GET", "buckets") {
+		buckets := []runscope.Bucket{}
+		for _, b := range s.buckets {
+			buckets = append(buckets, b.Bucket)
+		}
+		return ok(buckets)
+	}
+	if rt.match("POST", "buckets") {
+		return s.newBucket(rt)
+	}
+
+	b := s.findBucket(rt.segments[1])
+	if b == nil {
+		return notFound("bucket")
+	}
+
+	switch {
+	case rt.match("GET", "buckets", "*"):
+		return ok(b.Bucket)
+	case rt.match("DELETE", "buckets", "*"):
+		s.deleteBucket(b)
+		return noContent()
+	case rt.match("GET", "buckets", "*", "environments"):
+		return ok(nonNil(b.environments))
+	case rt.match("POST", "buckets", "*", "environments"):
+		return s.newEnvironment(&b.environments, "", rt)
+	case rt.matchPrefix("buckets", "*", "environments", "*"):
+		return s.dispatchEnvironment(b, rt)
+	case rt.match("GET", "buckets", "*", "tests"):
+		return s.listTests(b, rt)
+	case rt.match("POST", "buckets", "*", "tests"):
+		return s.newTest(b, rt)
+	case rt.matchPrefix("buckets", "*", "tests", "*"):
+		t := b.findTest(rt.segments[3])
+		if t == nil {
+			return notFound("test")
+		}
+		return s.dispatchTest(b, t, rt)
+	}
+	return notFound("route")
+}
+
+func (s *Server) dispatchEnvironment(b *bucket, rt route) (int, interface{}, error) {
+	id := rt.segments[3]
+	environments := &b.environments
+	i := findEnvironment(*environments, id)
+	if i < 0 && rt.method == "DELETE" {
+		// the API deletes test environments through the bucket path too
+		for _, t := range b.tests {
+			if j := findEnvironment(t.Environments, id); j >= 0 {
+				environments, i = &t.Environments, j
+				break
+			}
+		}
+	}
+	if i < 0 || len(rt.segments) != 4 {
+		return notFound("environment")
+	}
+
+	switch rt.method {
+	case "GET":
+		return ok((*environments)[i])
+	case "PUT":
+		return s.updateEnvironment(*environments, i, rt)
+	case "DELETE":
+		for _, t := range b.tests {
+			if t.DefaultEnvironmentID == id {
+				return badRequest(fmt.Errorf("environment %s is the default environment of test %s", id, t.ID))
+			}
+		}
+		*environments = append((*environments)[:i], (*environments)[i+1:]...)
+		return noContent()
+	}
+	return notFound("route")
+}
+
+func (s *Server) dispatchTest(b *bucket, t *test, rt route) (int, interface{}, error) {
+	switch {
+	case rt.match("GET", "buckets", "*", "tests", "*"):
+		return ok(t.Test)
+	case rt.match("PUT", "buckets", "*", "tests", "*"):
+		return s.updateTest(t, rt)
+	case rt.match("DELETE", "buckets", "*", "tests", "*"):
+		for i := range b.tests {
+			if b.tests[i] == t {
+				b.tests = append(b.tests[:i], b.tests[i+1:]...)
+				break
+			}
+		}
+		return noContent()
+
+	case rt.match("GET", "buckets", "*", "tests", "*", "steps"):
+		return ok(nonNil(t.Steps))
+	case rt.match("POST", "buckets", "*", "tests", "*", "steps"):
+		return s.newStep(t, rt)
+	case rt.matchPrefix("buckets", "*", "tests", "*", "steps", "*"):
+		return s.dispatchStep(t, rt)
+
+	case rt.match("GET", "buckets", "*", "tests", "*", "environments"):
+		return ok(nonNil(t.Environments))
+	case rt.match("POST", "buckets", "*", "tests", "*", "environments"):
+		return s.newEnvironment(&t.Environments, t.ID, rt)
+	case rt.matchPrefix("buckets", "*", "tests", "*", "environments", "*"):
+		i := findEnvironment(t.Environments, rt.segments[5])
+		if i < 0 || len(rt.segments) != 6 {
+			return notFound("environment")
+		}
+		switch rt.method {
+		case "GET":
+			return ok(t.Environments[i])
+		case "PUT":
+			return s.updateEnvironment(t.Environments, i, rt)
+		}
+
+	case rt.match("GET", "buckets", "*", "tests", "*", "schedules"):
+		return ok(nonNil(t.Schedules))
+	case rt.match("POST", "buckets", "*", "tests", "*", "schedules"):
+		return s.newSchedule(t, rt)
+	case rt.matchPrefix("buckets", "*", "tests", "*", "schedules", "*"):
+		return s.dispatchSchedule(t, rt)
+
+	case rt.match("GET", "buckets", "*", "tests", "*", "results"):
+		return s.listResults(t, rt)
+	case rt.match("GET", "buckets", "*", "tests", "*", "results", "*"):
+		return s.getResult(t, rt.segments[5])
+	}
+	return notFound("route")
+}
+
+func (s *Server) dispatchStep(t *test, rt route) (int, interface{}, error) {
+	id := rt.segments[5]
+	i := -1
+	for j, step := range t.Steps {
+		if step.ID == id {
+			i = j
+		}
+	}
+	if i < 0 || len(rt.segments) != 6 {
+		return notFound("step")
+	}
+
+	switch rt.method {
+	case "GET":
+		return ok(t.Steps[i])
+	case "PUT":
+		var step runscope.Step
+		if err := json.Unmarshal(rt.body, &step); err != nil {
+			return badRequest(err)
+		}
+		step.ID = id
+		t.Steps[i] = step
+		return ok(step)
+	case "DELETE":
+		t.Steps = append(t.Steps[:i], t.Steps[i+1:]...)
+		return noContent()
+	}
+	return notFound("route")
+}
+
+func (s *Server) dispatchSchedule(t *test, rt route) (int, interface{}, error) {
+	id := rt.segments[5]
+	i := -1
+	for j, schedule := range t.Schedules {
+		if schedule.ID == id {
+			i = j
+		}
+	}
+	if i < 0 || len(rt.segments) != 6 {
+		return notFound("schedule")
+	}
+
+	switch rt.method {
+	case "GET":
+		return ok(t.Schedules[i])
+	case "PUT":
+		var schedule runscope.Schedule
+		if err := json.Unmarshal(rt.body, &schedule); err != nil {
+			return badRequest(err)
+		}
+		schedule.ID = id
+		t.Schedules[i] = schedule
+		return ok(schedule)
+	case "DELETE":
+		t.Schedules = append(t.Schedules[:i], t.Schedules[i+1:]...)
+		return noContent()
+	}
+	return notFound("route")
+}
+
+func (s *Server) newBucket(rt route) (int, interface{}, error) {
+	var request runscope.NewBucketRequest
+	if err := json.Unmarshal(rt.body, &request); err != nil {
+		return badRequest(err)
+	}
+	if request.Name == "" {
+		return badRequest(fmt.Errorf("name is required"))
+	}
+
+	team := s.account.Teams[0]
+	for _, t := range s.account.Teams {
+		if t.ID == request.TeamUUID || t.UUID == request.TeamUUID {
+			team = t
+		}
+	}
+
+	key := newKey()
+	b := &bucket{triggerID: newID()}
+	b.Bucket = runscope.Bucket{
+		Name:           request.Name,
+		AuthToken:      newID(),
+		Key:            key,
+		Team:           team,
+		VerifySSL:      true,
+		CollectionsURL: fmt.Sprintf("%s/buckets/%s/collections", s.URL, key),
+		MessagesURL:    fmt.Sprintf("%s/buckets/%s/messages", s.URL, key),
+		TestsURL:       fmt.Sprintf("%s/buckets/%s/tests", s.URL, key),
+		TriggerURL:     fmt.Sprintf("%s/radar/bucket/%s/trigger", s.URL, b.triggerID),
+	}
+	s.buckets = append(s.buckets, b)
+	return http.StatusCreated, b.Bucket, nil
+}
+
+func (s *Server) deleteBucket(b *bucket) {
+	for i := range s.buckets {
+		if s.buckets[i] == b {
+			s.buckets = append(s.buckets[:i], s.buckets[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Server) listTests(b *bucket, rt route) (int, interface{}, error) {
+	tests := []runscope.Test{}
+	for _, t := range b.tests {
+		tests = append(tests, t.Test)
+	}
+
+	offset := intParam(rt.query, "offset", 0)
+	count := intParam(rt.query, "count", 10)
+	if offset > len(tests) {
+		offset = len(tests)
+	}
+	if offset+count < len(tests) {
+		tests = tests[:offset+count]
+	}
+	return ok(tests[offset:])
+}
+
+// newTest creates a test from either a NewTestRequest or a full test
+// definition as sent by ImportTest
+func (s *Server) newTest(b *bucket, rt route) (int, interface{}, error) {
+	var definition runscope.Test
+	if err := json.Unmarshal(rt.body, &definition); err != nil {
+		return badRequest(err)
+	}
+	if definition.Name == "" {
+		return badRequest(fmt.Errorf("name is required"))
+	}
+
+	t := &test{triggerID: newID()}
+	t.Test = runscope.Test{
+		Name:        definition.Name,
+		ID:          newID(),
+		Description: definition.Description,
+		CreatedAt:   int(now()),
+		CreatedBy: runscope.Person{
+			Name:  s.account.Name,
+			Email: s.account.Email,
+			ID:    s.account.ID,
+		},
+		TriggerURL: fmt.Sprintf("%s/radar/%s/trigger", s.URL, t.triggerID),
+		Steps:      []runscope.Step{},
+		Schedules:  []runscope.Schedule{},
+	}
+
+	for _, step := range definition.Steps {
+		step.ID = newID()
+		t.Steps = append(t.Steps, step)
+	}
+
+	environments := definition.Environments
+	if len(environments) == 0 {
+		environments = []runscope.Environment{{Name: "Test Settings", Regions: []string{"us1"}, VerifySSL: true}}
+	}
+	for _, environment := range environments {
+		environment.ID = newID()
+		environment.TestID = t.ID
+		t.Environments = append(t.Environments, environment)
+	}
+	t.DefaultEnvironmentID = t.Environments[0].ID
+
+	b.tests = append(b.tests, t)
+	return http.StatusCreated, t.Test, nil
+}
+
+// updateTest applies either an UpdateTestRequest, where steps is a list
+// of step IDs in their new order, or a full test definition as sent by
+// ReimportTest
+func (s *Server) updateTest(t *test, rt route) (int, interface{}, error) {
+	var request struct {
+		Name                 string          `json:"name"`
+		Description          string          `json:"description"`
+		DefaultEnvironmentID string          `json:"default_environment_id"`
+		Steps                json.RawMessage `json:"steps"`
+	}
+	if err := json.Unmarshal(rt.body, &request); err != nil {
+		return badRequest(err)
+	}
+
+	if request.Name != "" {
+		t.Name = request.Name
+	}
+	if request.Description != "" {
+		t.Description = request.Description
+	}
+	if request.DefaultEnvironmentID != "" {
+		if findEnvironment(t.Environments, request.DefaultEnvironmentID) < 0 {
+			return badRequest(fmt.Errorf("environment %s not found", request.DefaultEnvironmentID))
+		}
+		t.DefaultEnvironmentID = request.DefaultEnvironmentID
+	}
+
+	if len(request.Steps) == 0 || string(request.Steps) == "null" {
+		return ok(t.Test)
+	}
+
+	var order []string
+	if err := json.Unmarshal(request.Steps, &order); err == nil {
+		steps := []runscope.Step{}
+		for _, id := range order {
+			for _, step := range t.Steps {
+				if step.ID == id {
+					steps = append(steps, step)
+				}
+			}
+		}
+		if len(steps) != len(t.Steps) {
+			return badRequest(fmt.Errorf("steps must list every step of the test"))
+		}
+		t.Steps = steps
+		return ok(t.Test)
+	}
+
+	var steps []runscope.Step
+	if err := json.Unmarshal(request.Steps, &steps); err != nil {
+		return badRequest(err)
+	}
+	t.Steps = []runscope.Step{}
+	for _, step := range steps {
+		step.ID = newID()
+		t.Steps = append(t.Steps, step)
+	}
+	return ok(t.Test)
+}
+
+func (s *Server) newStep(t *test, rt route) (int, interface{}, error) {
+	var step runscope.Step
+	if err := json.Unmarshal(rt.body, &step); err != nil {
+		return badRequest(err)
+	}
+	if step.StepType == "" {
+		return badRequest(fmt.Errorf("step_type is required"))
+	}
+	step.ID = newID()
+	t.Steps = append(t.Steps, step)
+	return http.StatusCreated, step, nil
+}
+
+func (s *Server) newEnvironment(environments *[]runscope.Environment, testID string, rt route) (int, interface{}, error) {
+	var environment runscope.Environment
+	if err := json.Unmarshal(rt.body, &environment); err != nil {
+		return badRequest(err)
+	}
+	if environment.Name == "" {
+		return badRequest(fmt.Errorf("name is required"))
+	}
+	environment.ID = newID()
+	environment.TestID = testID
+	*environments = append(*environments, environment)
+	return http.StatusCreated, environment, nil
+}
+
+func (s *Server) updateEnvironment(environments []runscope.Environment, i int, rt route) (int, interface{}, error) {
+	var environment runscope.Environment
+	if err := json.Unmarshal(rt.body, &environment); err != nil {
+		return badRequest(err)
+	}
+	environment.ID = environments[i].ID
+	environment.TestID = environments[i].TestID
+	environments[i] = environment
+	return ok(environment)
+}
+
+func (s *Server) newSchedule(t *test, rt route) (int, interface{}, error) {
+	var schedule runscope.Schedule
+	if err := json.Unmarshal(rt.body, &schedule); err != nil {
+		return badRequest(err)
+	}
+	if findEnvironment(t.Environments, schedule.EnvironmentID) < 0 && s.findSharedEnvironment(schedule.EnvironmentID) < 0 {
+		return badRequest(fmt.Errorf("environment %s not found", schedule.EnvironmentID))
+	}
+	schedule.ID = newID()
+	t.Schedules = append(t.Schedules, schedule)
+	return http.StatusCreated, schedule, nil
+}
+
+// listResults returns results newest first, honoring the count, since
+// and before filters
+func (s *Server) listResults(t *test, rt route) (int, interface{}, error) {
+	count := intParam(rt.query, "count", 10)
+	since := floatParam(rt.query, "since")
+	before := floatParam(rt.query, "before")
+
+	results := []runscope.Result{}
+	for i := len(t.results) - 1; i >= 0; i-- {
+		result := t.results[i]
+		if since != 0 && result.StartedAt <= since {
+			continue
+		}
+		if before != 0 && result.StartedAt >= before {
+			continue
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].StartedAt > results[j].StartedAt
+	})
+	if len(results) > count {
+		results = results[:count]
+	}
+	return ok(results)
+}
+
+func (s *Server) getResult(t *test, testRunID string) (int, interface{}, error) {
+	if len(t.results) == 0 {
+		return notFound("result")
+	}
+	if testRunID == "latest" {
+		latest := t.results[0]
+		for _, result := range t.results {
+			if result.StartedAt >= latest.StartedAt {
+				latest = result
+			}
+		}
+		return ok(latest)
+	}
+	for _, result := range t.results {
+		if result.TestRunID == testRunID {
+			return ok(result)
+		}
+	}
+	return notFound("result")
+}
+
+// trigger starts runs for a test (radar/:trigger_id/trigger) or every
+// test in a bucket (radar/bucket/:trigger_id/trigger)
+func (s *Server) trigger(rt route) (int, interface{}, error) {
+	var targets []*test
+	var b *bucket
+
+	switch {
+	case rt.matchAny("radar", "bucket", "*", "trigger"):
+		for _, candidate := range s.buckets {
+			if candidate.triggerID == rt.segments[2] {
+				b = candidate
+				targets = candidate.tests
+			}
+		}
+	case rt.matchAny("radar", "*", "trigger"):
+		for _, candidate := range s.buckets {
+			for _, t := range candidate.tests {
+				if t.triggerID == rt.segments[1] {
+					b = candidate
+					targets = []*test{t}
+				}
+			}
+		}
+	default:
+		return notFound("route")
+	}
+	if b == nil {
+		return notFound("trigger")
+	}
+
+	environmentID := firstParam(rt.query, "runscope_environment")
+	regions := rt.query["runscope_region"]
+	variables := map[string]string{}
+	for key, values := range rt.query {
+		if !strings.HasPrefix(key, "runscope_") && len(values) > 0 {
+			variables[key] = values[0]
+		}
+	}
+	if len(rt.body) > 0 {
+		var body map[string]string
+		if err := json.Unmarshal(rt.body, &body); err != nil {
+			return badRequest(err)
+		}
+		for key, value := range body {
+			variables[key] = value
+		}
+	}
+
+	result := runscope.TriggerResult{Runs: []runscope.TestRun{}}
+	for _, t := range targets {
+		environment, found := s.runEnvironment(b, t, environmentID)
+		if !found {
+			result.RunsFailed++
+			continue
+		}
+
+		runRegions := regions
+		if len(runRegions) == 0 {
+			runRegions = environment.Regions
+		}
+		if len(runRegions) == 0 {
+			runRegions = []string{"us1"}
+		}
+
+		for _, region := range runRegions {
+			run := s.startRun(b, t, environment, region, variables)
+			result.Runs = append(result.Runs, run)
+			result.RunsStarted++
+		}
+	}
+	result.RunsTotal = result.RunsStarted + result.RunsFailed
+	return http.StatusCreated, result, nil
+}
+
+func (s *Server) runEnvironment(b *bucket, t *test, environmentID string) (runscope.Environment, bool) {
+	if environmentID == "" {
+		environmentID = t.DefaultEnvironmentID
+	}
+	if i := findEnvironment(t.Environments, environmentID); i >= 0 {
+		return t.Environments[i], true
+	}
+	if i := findEnvironment(b.environments, environmentID); i >= 0 {
+		return b.environments[i], true
+	}
+	return runscope.Environment{}, false
+}
+
+func (s *Server) startRun(b *bucket, t *test, environment runscope.Environment, region string, variables map[string]string) runscope.TestRun {
+	runID := newID()
+	started := now()
+
+	run := runscope.TestRun{
+		BucketKey:       b.Key,
+		EnvironmentID:   environment.ID,
+		EnvironmentName: environment.Name,
+		Region:          region,
+		Status:          "init",
+		TestID:          t.ID,
+		TestName:        t.Name,
+		TestRunID:       runID,
+		TestRunURL:      fmt.Sprintf("%s/buckets/%s/tests/%s/results/%s", s.URL, b.Key, t.ID, runID),
+		TestURL:         fmt.Sprintf("%s/buckets/%s/tests/%s", s.URL, b.Key, t.ID),
+		Variables:       variables,
+	}
+
+	requests := 0
+	for _, step := range t.Steps {
+		if step.StepType == "request" {
+			requests++
+		}
+	}
+	t.results = append(t.results, runscope.Result{
+		BucketKey:        b.Key,
+		TestID:           t.ID,
+		TestRunID:        runID,
+		TestRunURL:       run.TestRunURL,
+		EnvironmentID:    environment.ID,
+		EnvironmentName:  environment.Name,
+		Region:           region,
+		Result:           s.RunResult,
+		RequestsExecuted: requests,
+		StartedAt:        started,
+		FinishedAt:       started,
+	})
+	return run
+}
+
+func (s *Server) findBucket(key string) *bucket {
+	for _, b := range s.buckets {
+		if b.Key == key {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *Server) findTest(bucketKey string, testID string) *test {
+	if b := s.findBucket(bucketKey); b != nil {
+		return b.findTest(testID)
+	}
+	return nil
+}
+
+func (s *Server) findSharedEnvironment(id string) int {
+	for _, b := range s.buckets {
+		if i := findEnvironment(b.environments, id); i >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *bucket) findTest(id string) *test {
+	for _, t := range b.tests {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func findEnvironment(environments []runscope.Environment, id string) int {
+	for i, environment := range environments {
+		if environment.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// match reports whether the route has the given method and path, where
+// "*" matches any single segment
+func (rt route) match(method string, segments ...string) bool {
+	return rt.method == method && rt.matchAny(segments...)
+}
+
+func (rt route) matchAny(segments ...string) bool {
+	return len(rt.segments) == len(segments) && rt.matchPrefix(segments...)
+}
+
+func (rt route) matchPrefix(segments ...string) bool {
+	if len(rt.segments) < len(segments) {
+		return false
+	}
+	for i, segment := range segments {
+		if segment != "*" && segment != rt.segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeData(w http.ResponseWriter, code int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if code == http.StatusNoContent {
+		w.WriteHeader(code)
+		return
+	}
+	writeEnvelope(w, code, data, nil, "success")
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	writeEnvelope(w, code, nil, &runscope.Error{Status: code, Message: message}, "error")
+}
+
+// writeEnvelope writes the Response{Data, Error, Meta} structure used by
+// every Runscope API response
+func writeEnvelope(w http.ResponseWriter, code int, data interface{}, apiErr *runscope.Error, status string) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  data,
+		"error": apiErr,
+		"meta":  runscope.Meta{Status: status},
+	})
+}
+
+func ok(data interface{}) (int, interface{}, error) {
+	return http.StatusOK, data, nil
+}
+
+func noContent() (int, interface{}, error) {
+	return http.StatusNoContent, nil, nil
+}
+
+func notFound(resource string) (int, interface{}, error) {
+	return http.StatusNotFound, nil, fmt.Errorf("%s not found", resource)
+}
+
+func badRequest(err error) (int, interface{}, error) {
+	return http.StatusBadRequest, nil, err
+}
+
+// nonNil returns an empty slice instead of nil so lists encode as []
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func firstParam(query map[string][]string, key string) string {
+	if values := query[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func intParam(query map[string][]string, key string, fallback int) int {
+	value, err := strconv.Atoi(firstParam(query, key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+func floatParam(query map[string][]string, key string) float64 {
+	value, _ := strconv.ParseFloat(firstParam(query, key), 64)
+	return value
+}
+
+func now() float64 {
+	return float64(time.Now().UnixNano()) / 1.0e9
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	h := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+func newKey() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
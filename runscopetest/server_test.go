@@ -0,0 +1,230 @@
+package runscopetest
+
+import (
+	"testing"
+	"time"
+
+	runscope "github.com/nextrevision/go-runscope"
+)
+
+func TestServerBuckets(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	bucket, err := client.NewBucket(&runscope.NewBucketRequest{Name: "Mobile Apps"})
+	if err != nil {
+		t.Fatalf("NewBucket returned error: %v", err)
+	}
+	if bucket.Key == "" || bucket.TriggerURL == "" {
+		t.Errorf("NewBucket returned incomplete bucket: %+v", bucket)
+	}
+
+	buckets, err := client.ListBuckets()
+	if err != nil || len(buckets) != 1 || buckets[0].Name != "Mobile Apps" {
+		t.Errorf("ListBuckets returned %+v, %v", buckets, err)
+	}
+
+	if err := client.DeleteBucket(bucket.Key); err != nil {
+		t.Fatalf("DeleteBucket returned error: %v", err)
+	}
+	if _, err := client.GetBucket(bucket.Key); !runscope.IsNotFound(err) {
+		t.Errorf("GetBucket after delete returned %v, want not found", err)
+	}
+}
+
+func TestServerTests(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	bucket, _ := client.NewBucket(&runscope.NewBucketRequest{Name: "Mobile Apps"})
+	for i := 0; i < 60; i++ {
+		if _, err := client.NewTest(bucket.Key, runscope.NewTestRequest{Name: "Sample Test"}); err != nil {
+			t.Fatalf("NewTest returned error: %v", err)
+		}
+	}
+
+	tests, err := client.ListAllTests(bucket.Key)
+	if err != nil || len(tests) != 60 {
+		t.Fatalf("ListAllTests returned %d tests, %v", len(tests), err)
+	}
+
+	test, err := client.UpdateTest(bucket.Key, tests[0].ID, runscope.UpdateTestRequest{Name: "Renamed"})
+	if err != nil || test.Name != "Renamed" {
+		t.Errorf("UpdateTest returned %+v, %v", test, err)
+	}
+
+	imported, err := client.ImportTest(bucket.Key, []byte(`{"name": "Imported", "steps": [{"step_type": "request", "method": "GET", "url": "https://yourapihere.com/"}]}`))
+	if err != nil || len(imported.Steps) != 1 || imported.Steps[0].ID == "" {
+		t.Errorf("ImportTest returned %+v, %v", imported, err)
+	}
+
+	if err := client.DeleteTest(bucket.Key, test.ID); err != nil {
+		t.Errorf("DeleteTest returned error: %v", err)
+	}
+	if _, err := client.GetTest(bucket.Key, test.ID); !runscope.IsNotFound(err) {
+		t.Errorf("GetTest after delete returned %v, want not found", err)
+	}
+}
+
+func TestServerSteps(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	bucket, _ := client.NewBucket(&runscope.NewBucketRequest{Name: "Mobile Apps"})
+	test, _ := client.NewTest(bucket.Key, runscope.NewTestRequest{Name: "Sample Test"})
+
+	first, err := client.NewStep(bucket.Key, test.ID, runscope.Step{StepType: "request", Method: "GET", URL: "https://yourapihere.com/"})
+	if err != nil {
+		t.Fatalf("NewStep returned error: %v", err)
+	}
+	second, _ := client.NewStep(bucket.Key, test.ID, runscope.Step{StepType: "pause", Duration: 5})
+
+	if _, err := client.UpdateTest(bucket.Key, test.ID, runscope.UpdateTestRequest{Steps: []string{second.ID, first.ID}}); err != nil {
+		t.Fatalf("UpdateTest returned error: %v", err)
+	}
+	steps, err := client.ListSteps(bucket.Key, test.ID)
+	if err != nil || len(steps) != 2 || steps[0].ID != second.ID {
+		t.Errorf("ListSteps returned %+v, %v", steps, err)
+	}
+
+	first.Method = "POST"
+	updated, err := client.UpdateStep(bucket.Key, test.ID, first.ID, first)
+	if err != nil || updated.Method != "POST" {
+		t.Errorf("UpdateStep returned %+v, %v", updated, err)
+	}
+
+	if err := client.DeleteStep(bucket.Key, test.ID, second.ID); err != nil {
+		t.Errorf("DeleteStep returned error: %v", err)
+	}
+	if _, err := client.GetStep(bucket.Key, test.ID, second.ID); !runscope.IsNotFound(err) {
+		t.Errorf("GetStep after delete returned %v, want not found", err)
+	}
+}
+
+func TestServerEnvironmentsAndSchedules(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	bucket, _ := client.NewBucket(&runscope.NewBucketRequest{Name: "Mobile Apps"})
+	test, _ := client.NewTest(bucket.Key, runscope.NewTestRequest{Name: "Sample Test"})
+
+	shared, err := client.NewSharedEnvironment(bucket.Key, runscope.Environment{Name: "Production", InitialVariables: map[string]string{"base_url": "https://api.example.com"}})
+	if err != nil {
+		t.Fatalf("NewSharedEnvironment returned error: %v", err)
+	}
+	environments, _ := client.ListSharedEnvironments(bucket.Key)
+	if len(environments) != 1 {
+		t.Errorf("ListSharedEnvironments returned %d environments, want 1", len(environments))
+	}
+
+	environment, err := client.NewTestEnvironment(bucket.Key, test.ID, runscope.Environment{Name: "Staging", ParentEnvironmentID: shared.ID})
+	if err != nil || environment.TestID != test.ID {
+		t.Fatalf("NewTestEnvironment returned %+v, %v", environment, err)
+	}
+	environment.VerifySSL = true
+	if updated, err := client.UpdateTestEnvironment(bucket.Key, test.ID, environment.ID, environment); err != nil || !updated.VerifySSL {
+		t.Errorf("UpdateTestEnvironment returned %+v, %v", updated, err)
+	}
+
+	schedule, err := client.NewSchedule(bucket.Key, test.ID, runscope.Schedule{EnvironmentID: environment.ID, Interval: "1h"})
+	if err != nil {
+		t.Fatalf("NewSchedule returned error: %v", err)
+	}
+	if _, err := client.NewSchedule(bucket.Key, test.ID, runscope.Schedule{EnvironmentID: "missing", Interval: "1h"}); err == nil {
+		t.Error("NewSchedule with a missing environment should fail")
+	}
+	schedule.Interval = "5m"
+	if updated, err := client.UpdateSchedule(bucket.Key, test.ID, schedule.ID, schedule); err != nil || updated.Interval != "5m" {
+		t.Errorf("UpdateSchedule returned %+v, %v", updated, err)
+	}
+	if err := client.DeleteSchedule(bucket.Key, test.ID, schedule.ID); err != nil {
+		t.Errorf("DeleteSchedule returned error: %v", err)
+	}
+
+	if err := client.DeleteEnvironment(bucket.Key, environment.ID); err != nil {
+		t.Errorf("DeleteEnvironment returned error: %v", err)
+	}
+	if environments, _ := client.ListTestEnvironments(bucket.Key, test.ID); len(environments) != 1 {
+		t.Errorf("ListTestEnvironments returned %d environments, want only the default", len(environments))
+	}
+	if err := client.DeleteEnvironment(bucket.Key, test.DefaultEnvironmentID); err == nil {
+		t.Error("DeleteEnvironment of the default environment should fail")
+	}
+}
+
+func TestServerTriggerAndResults(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	bucket, _ := client.NewBucket(&runscope.NewBucketRequest{Name: "Mobile Apps"})
+	test, _ := client.NewTest(bucket.Key, runscope.NewTestRequest{Name: "Sample Test"})
+	client.NewTest(bucket.Key, runscope.NewTestRequest{Name: "Other Test"})
+
+	triggered, err := client.Trigger(test.TriggerURL + "?runscope_region=us1&runscope_region=eu1&user=grace")
+	if err != nil {
+		t.Fatalf("Trigger returned error: %v", err)
+	}
+	if triggered.RunsStarted != 2 || triggered.Runs[0].Variables["user"] != "grace" {
+		t.Errorf("Trigger returned %+v", triggered)
+	}
+
+	result, err := client.GetResult(bucket.Key, test.ID, triggered.Runs[0].TestRunID)
+	if err != nil || result.Result != "pass" {
+		t.Errorf("GetResult returned %+v, %v", result, err)
+	}
+
+	triggered, err = client.Trigger(bucket.TriggerURL)
+	if err != nil || triggered.RunsStarted != 2 {
+		t.Errorf("Trigger bucket returned %+v, %v", triggered, err)
+	}
+
+	server.AddResult(bucket.Key, test.ID, runscope.Result{Result: "fail", StartedAt: float64(time.Now().Add(time.Hour).Unix())})
+	latest, err := client.GetResultLatest(bucket.Key, test.ID)
+	if err != nil || latest.Result != "fail" {
+		t.Errorf("GetResultLatest returned %+v, %v", latest, err)
+	}
+
+	results, err := client.ListResults(bucket.Key, test.ID)
+	if err != nil || len(results) != 4 || results[0].Result != "fail" {
+		t.Errorf("ListResults returned %+v, %v", results, err)
+	}
+
+	before := time.Now().Add(time.Minute)
+	results, err = client.FilterResults(bucket.Key, test.ID, 50, nil, &before)
+	if err != nil || len(results) != 3 {
+		t.Errorf("FilterResults returned %d results, %v", len(results), err)
+	}
+}
+
+func TestServerAccount(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	account, err := client.GetAccount()
+	if err != nil || len(account.Teams) != 1 {
+		t.Fatalf("GetAccount returned %+v, %v", account, err)
+	}
+	team := account.Teams[0]
+
+	server.AddIntegration(team.ID, runscope.Integration{Type: "pagerduty", Description: "PagerDuty"})
+	integrations, err := client.ListIntegrations(team.ID)
+	if err != nil || len(integrations) != 1 {
+		t.Errorf("ListIntegrations returned %+v, %v", integrations, err)
+	}
+
+	people, err := client.ListPeople(team.ID)
+	if err != nil || len(people) != 1 || people[0].Email != account.Email {
+		t.Errorf("ListPeople returned %+v, %v", people, err)
+	}
+
+	regions, err := client.ListRegions()
+	if err != nil || len(regions.Regions) == 0 {
+		t.Errorf("ListRegions returned %+v, %v", regions, err)
+	}
+}
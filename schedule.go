@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,10 +17,15 @@ type Schedule struct {
 
 // ListSchedules returns all schedules for a given test
 func (client *Client) ListSchedules(bucketKey string, testID string) ([]Schedule, error) {
+	return client.ListSchedulesWithContext(context.Background(), bucketKey, testID)
+}
+
+// ListSchedulesWithContext is the same as ListSchedules, bound to the supplied context
+func (client *Client) ListSchedulesWithContext(ctx context.Context, bucketKey string, testID string) ([]Schedule, error) {
 	var schedules = []Schedule{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/schedules", bucketKey, testID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return schedules, err
 	}
@@ -30,10 +36,15 @@ func (client *Client) ListSchedules(bucketKey string, testID string) ([]Schedule
 
 // GetSchedule returns detailed output for a given schedule ID
 func (client *Client) GetSchedule(bucketKey string, testID string, scheduleID string) (Schedule, error) {
+	return client.GetScheduleWithContext(context.Background(), bucketKey, testID, scheduleID)
+}
+
+// GetScheduleWithContext is the same as GetSchedule, bound to the supplied context
+func (client *Client) GetScheduleWithContext(ctx context.Context, bucketKey string, testID string, scheduleID string) (Schedule, error) {
 	var schedule = Schedule{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/schedules/%s", bucketKey, testID, scheduleID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return schedule, err
 	}
@@ -44,6 +55,11 @@ func (client *Client) GetSchedule(bucketKey string, testID string, scheduleID st
 
 // NewSchedule creates a new schedule for a test
 func (client *Client) NewSchedule(bucketKey string, testID string, schedule Schedule) (Schedule, error) {
+	return client.NewScheduleWithContext(context.Background(), bucketKey, testID, schedule)
+}
+
+// NewScheduleWithContext is the same as NewSchedule, bound to the supplied context
+func (client *Client) NewScheduleWithContext(ctx context.Context, bucketKey string, testID string, schedule Schedule) (Schedule, error) {
 	var newSchedule = Schedule{}
 
 	if schedule.EnvironmentID == "" {
@@ -61,7 +77,7 @@ func (client *Client) NewSchedule(bucketKey string, testID string, schedule Sche
 		return newSchedule, err
 	}
 
-	content, err := client.Post(path, data)
+	content, err := client.PostWithContext(ctx, path, data)
 	if err != nil {
 		return newSchedule, err
 	}
@@ -72,6 +88,11 @@ func (client *Client) NewSchedule(bucketKey string, testID string, schedule Sche
 
 // UpdateSchedule updates a given test schedule
 func (client *Client) UpdateSchedule(bucketKey string, testID string, scheduleID string, schedule Schedule) (Schedule, error) {
+	return client.UpdateScheduleWithContext(context.Background(), bucketKey, testID, scheduleID, schedule)
+}
+
+// UpdateScheduleWithContext is the same as UpdateSchedule, bound to the supplied context
+func (client *Client) UpdateScheduleWithContext(ctx context.Context, bucketKey string, testID string, scheduleID string, schedule Schedule) (Schedule, error) {
 	var newSchedule = Schedule{}
 
 	if schedule.EnvironmentID == "" {
@@ -89,7 +110,7 @@ func (client *Client) UpdateSchedule(bucketKey string, testID string, scheduleID
 		return newSchedule, err
 	}
 
-	content, err := client.Put(path, data)
+	content, err := client.PutWithContext(ctx, path, data)
 	if err != nil {
 		return newSchedule, err
 	}
@@ -100,6 +121,11 @@ func (client *Client) UpdateSchedule(bucketKey string, testID string, scheduleID
 
 // DeleteSchedule removes a test schedule
 func (client *Client) DeleteSchedule(bucketKey string, testID string, scheduleID string) error {
+	return client.DeleteScheduleWithContext(context.Background(), bucketKey, testID, scheduleID)
+}
+
+// DeleteScheduleWithContext is the same as DeleteSchedule, bound to the supplied context
+func (client *Client) DeleteScheduleWithContext(ctx context.Context, bucketKey string, testID string, scheduleID string) error {
 	path := fmt.Sprintf("buckets/%s/tests/%s/schedules/%s", bucketKey, testID, scheduleID)
-	return client.Delete(path)
+	return client.DeleteWithContext(ctx, path)
 }
@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -76,11 +77,16 @@ type Script struct {
 
 // ListSteps returns all steps for a given test
 func (client *Client) ListSteps(bucketKey string, testID string) ([]Step, error) {
+	return client.ListStepsWithContext(context.Background(), bucketKey, testID)
+}
+
+// ListStepsWithContext is the same as ListSteps, bound to the supplied context
+func (client *Client) ListStepsWithContext(ctx context.Context, bucketKey string, testID string) ([]Step, error) {
 	var steps = []Step{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/steps", bucketKey, testID)
 
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return steps, err
 	}
@@ -91,10 +97,15 @@ func (client *Client) ListSteps(bucketKey string, testID string) ([]Step, error)
 
 // GetStep returns details for a given step
 func (client *Client) GetStep(bucketKey string, testID string, stepID string) (Step, error) {
+	return client.GetStepWithContext(context.Background(), bucketKey, testID, stepID)
+}
+
+// GetStepWithContext is the same as GetStep, bound to the supplied context
+func (client *Client) GetStepWithContext(ctx context.Context, bucketKey string, testID string, stepID string) (Step, error) {
 	var step = Step{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s/steps/%s", bucketKey, testID, stepID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return step, err
 	}
@@ -105,15 +116,24 @@ func (client *Client) GetStep(bucketKey string, testID string, stepID string) (S
 
 // NewStep creates a new step for a given test
 func (client *Client) NewStep(bucketKey string, testID string, step Step) (Step, error) {
+	return client.NewStepWithContext(context.Background(), bucketKey, testID, step)
+}
+
+// NewStepWithContext is the same as NewStep, bound to the supplied context
+func (client *Client) NewStepWithContext(ctx context.Context, bucketKey string, testID string, step Step) (Step, error) {
 	var newStep = Step{}
 
+	if err := validateStep(step); err != nil {
+		return newStep, err
+	}
+
 	path := fmt.Sprintf("buckets/%s/tests/%s/steps", bucketKey, testID)
 	data, err := json.Marshal(&step)
 	if err != nil {
 		return newStep, err
 	}
 
-	content, err := client.Post(path, data)
+	content, err := client.PostWithContext(ctx, path, data)
 	if err != nil {
 		return newStep, err
 	}
@@ -124,15 +144,24 @@ func (client *Client) NewStep(bucketKey string, testID string, step Step) (Step,
 
 // UpdateStep updates an existing step according to the Step struct passed to it
 func (client *Client) UpdateStep(bucketKey string, testID string, stepID string, step Step) (Step, error) {
+	return client.UpdateStepWithContext(context.Background(), bucketKey, testID, stepID, step)
+}
+
+// UpdateStepWithContext is the same as UpdateStep, bound to the supplied context
+func (client *Client) UpdateStepWithContext(ctx context.Context, bucketKey string, testID string, stepID string, step Step) (Step, error) {
 	var newStep = Step{}
 
+	if err := validateStep(step); err != nil {
+		return newStep, err
+	}
+
 	path := fmt.Sprintf("buckets/%s/tests/%s/steps/%s", bucketKey, testID, stepID)
 	data, err := json.Marshal(&step)
 	if err != nil {
 		return newStep, err
 	}
 
-	content, err := client.Put(path, data)
+	content, err := client.PutWithContext(ctx, path, data)
 	if err != nil {
 		return newStep, err
 	}
@@ -143,6 +172,11 @@ func (client *Client) UpdateStep(bucketKey string, testID string, stepID string,
 
 // DeleteStep removes a step from a test
 func (client *Client) DeleteStep(bucketKey string, testID string, stepID string) error {
+	return client.DeleteStepWithContext(context.Background(), bucketKey, testID, stepID)
+}
+
+// DeleteStepWithContext is the same as DeleteStep, bound to the supplied context
+func (client *Client) DeleteStepWithContext(ctx context.Context, bucketKey string, testID string, stepID string) error {
 	path := fmt.Sprintf("buckets/%s/tests/%s/steps/%s", bucketKey, testID, stepID)
-	return client.Delete(path)
+	return client.DeleteWithContext(ctx, path)
 }
@@ -0,0 +1,287 @@
+package runscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Step types supported by Runscope
+const (
+	StepTypeRequest        = "request"
+	StepTypePause          = "pause"
+	StepTypeCondition      = "condition"
+	StepTypeSubtest        = "subtest"
+	StepTypeGhostInspector = "ghost-inspector"
+)
+
+// StepDefinition is implemented by the typed step variants. Each variant
+// only carries the fields of its step type and marshals with the
+// matching step_type.
+type StepDefinition interface {
+	// StepType returns the step_type of the variant
+	StepType() string
+	// ToStep converts the variant to the untyped Step
+	ToStep() Step
+}
+
+// RequestStep makes a HTTP request and checks its response
+type RequestStep struct {
+	ID         string              `json:"id,omitempty"`
+	Method     string              `json:"method"`
+	URL        string              `json:"url"`
+	Body       string              `json:"body,omitempty"`
+	Auth       *Auth               `json:"auth,omitempty"`
+	Form       map[string][]string `json:"form,omitempty"`
+	Assertions []Assertion         `json:"assertions"`
+	Variables  []Variable          `json:"variables"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Scripts    []string            `json:"scripts"`
+	Note       string              `json:"note,omitempty"`
+}
+
+// PauseStep waits for Duration seconds before the next step
+type PauseStep struct {
+	ID       string `json:"id,omitempty"`
+	Duration int    `json:"duration"`
+	Note     string `json:"note,omitempty"`
+}
+
+// ConditionStep runs its Steps only when the comparison of LeftValue and
+// RightValue holds
+type ConditionStep struct {
+	ID         string          `json:"id,omitempty"`
+	LeftValue  string          `json:"left_value"`
+	Comparison string          `json:"comparison"`
+	RightValue string          `json:"right_value"`
+	Steps      StepDefinitions `json:"steps"`
+	Note       string          `json:"note,omitempty"`
+}
+
+// SubtestStep runs another test
+type SubtestStep struct {
+	ID     string `json:"id,omitempty"`
+	TestID string `json:"test_id"`
+	Note   string `json:"note,omitempty"`
+}
+
+// GhostInspectorStep runs a Ghost Inspector suite or test
+type GhostInspectorStep struct {
+	ID               string `json:"id,omitempty"`
+	IntegrationID    string `json:"integration_id"`
+	SuiteID          string `json:"suite_id,omitempty"`
+	TestID           string `json:"test_id,omitempty"`
+	URL              string `json:"url,omitempty"`
+	IsCustomStartURL bool   `json:"is_custom_start_url"`
+	Note             string `json:"note,omitempty"`
+}
+
+// StepType returns "request"
+func (step RequestStep) StepType() string { return StepTypeRequest }
+
+// StepType returns "pause"
+func (step PauseStep) StepType() string { return StepTypePause }
+
+// StepType returns "condition"
+func (step ConditionStep) StepType() string { return StepTypeCondition }
+
+// StepType returns "subtest"
+func (step SubtestStep) StepType() string { return StepTypeSubtest }
+
+// StepType returns "ghost-inspector"
+func (step GhostInspectorStep) StepType() string { return StepTypeGhostInspector }
+
+// ToStep converts the request step to a Step
+func (step RequestStep) ToStep() Step { return toStep(step) }
+
+// ToStep converts the pause step to a Step
+func (step PauseStep) ToStep() Step { return toStep(step) }
+
+// ToStep converts the condition step to a Step
+func (step ConditionStep) ToStep() Step { return toStep(step) }
+
+// ToStep converts the subtest step to a Step
+func (step SubtestStep) ToStep() Step { return toStep(step) }
+
+// ToStep converts the Ghost Inspector step to a Step
+func (step GhostInspectorStep) ToStep() Step { return toStep(step) }
+
+// MarshalJSON encodes the step with its step_type
+func (step RequestStep) MarshalJSON() ([]byte, error) {
+	type alias RequestStep
+	return json.Marshal(struct {
+		StepType string `json:"step_type"`
+		alias
+	}{StepTypeRequest, alias(step)})
+}
+
+// MarshalJSON encodes the step with its step_type
+func (step PauseStep) MarshalJSON() ([]byte, error) {
+	type alias PauseStep
+	return json.Marshal(struct {
+		StepType string `json:"step_type"`
+		alias
+	}{StepTypePause, alias(step)})
+}
+
+// MarshalJSON encodes the step with its step_type
+func (step ConditionStep) MarshalJSON() ([]byte, error) {
+	type alias ConditionStep
+	return json.Marshal(struct {
+		StepType string `json:"step_type"`
+		alias
+	}{StepTypeCondition, alias(step)})
+}
+
+// MarshalJSON encodes the step with its step_type
+func (step SubtestStep) MarshalJSON() ([]byte, error) {
+	type alias SubtestStep
+	return json.Marshal(struct {
+		StepType string `json:"step_type"`
+		alias
+	}{StepTypeSubtest, alias(step)})
+}
+
+// MarshalJSON encodes the step with its step_type
+func (step GhostInspectorStep) MarshalJSON() ([]byte, error) {
+	type alias GhostInspectorStep
+	return json.Marshal(struct {
+		StepType string `json:"step_type"`
+		alias
+	}{StepTypeGhostInspector, alias(step)})
+}
+
+func toStep(definition StepDefinition) Step {
+	var step = Step{}
+
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return step
+	}
+	json.Unmarshal(data, &step)
+	return step
+}
+
+// UnmarshalStepDefinition decodes a step into the variant matching its
+// step_type. Steps without a step_type are requests, as in Runscope.
+func UnmarshalStepDefinition(data []byte) (StepDefinition, error) {
+	var header struct {
+		StepType string `json:"step_type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	switch header.StepType {
+	case StepTypeRequest, "":
+		return decodeStep[RequestStep](data)
+	case StepTypePause:
+		return decodeStep[PauseStep](data)
+	case StepTypeCondition:
+		return decodeStep[ConditionStep](data)
+	case StepTypeSubtest:
+		return decodeStep[SubtestStep](data)
+	case StepTypeGhostInspector:
+		return decodeStep[GhostInspectorStep](data)
+	}
+	return nil, fmt.Errorf("Unknown step_type %q", header.StepType)
+}
+
+func decodeStep[T StepDefinition](data []byte) (StepDefinition, error) {
+	var step T
+	err := json.Unmarshal(data, &step)
+	return step, err
+}
+
+// StepDefinitions is a list of typed steps decoding each step into the
+// variant matching its step_type
+type StepDefinitions []StepDefinition
+
+// UnmarshalJSON decodes a JSON array of steps
+func (definitions *StepDefinitions) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	result := make(StepDefinitions, 0, len(raw))
+	for _, item := range raw {
+		definition, err := UnmarshalStepDefinition(item)
+		if err != nil {
+			return err
+		}
+		result = append(result, definition)
+	}
+	*definitions = result
+	return nil
+}
+
+// Definition converts the step to the typed variant matching its StepType
+func (step Step) Definition() (StepDefinition, error) {
+	data, err := json.Marshal(step)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalStepDefinition(data)
+}
+
+// NewStepDefinition creates a new step for a given test from a typed step
+func (client *Client) NewStepDefinition(bucketKey string, testID string, definition StepDefinition) (StepDefinition, error) {
+	return client.NewStepDefinitionWithContext(context.Background(), bucketKey, testID, definition)
+}
+
+// NewStepDefinitionWithContext is the same as NewStepDefinition, bound to the supplied context
+func (client *Client) NewStepDefinitionWithContext(ctx context.Context, bucketKey string, testID string, definition StepDefinition) (StepDefinition, error) {
+	if err := validateStep(definition.ToStep()); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("buckets/%s/tests/%s/steps", bucketKey, testID)
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := client.PostWithContext(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalStepDefinition(content)
+}
+
+// UpdateStepDefinition updates an existing step from a typed step
+func (client *Client) UpdateStepDefinition(bucketKey string, testID string, stepID string, definition StepDefinition) (StepDefinition, error) {
+	return client.UpdateStepDefinitionWithContext(context.Background(), bucketKey, testID, stepID, definition)
+}
+
+// UpdateStepDefinitionWithContext is the same as UpdateStepDefinition, bound to the supplied context
+func (client *Client) UpdateStepDefinitionWithContext(ctx context.Context, bucketKey string, testID string, stepID string, definition StepDefinition) (StepDefinition, error) {
+	if err := validateStep(definition.ToStep()); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("buckets/%s/tests/%s/steps/%s", bucketKey, testID, stepID)
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := client.PutWithContext(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalStepDefinition(content)
+}
+
+// unmarshalStepDefinition decodes a response envelope holding a step
+func unmarshalStepDefinition(content []byte) (StepDefinition, error) {
+	var raw json.RawMessage
+	if err := unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	return UnmarshalStepDefinition(raw)
+}
@@ -0,0 +1,136 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestStepDefinitionMarshal(t *testing.T) {
+	tests := []struct {
+		definition StepDefinition
+		want       string
+	}{
+		{
+			PauseStep{Duration: 5},
+			`{"step_type":"pause","duration":5}`,
+		},
+		{
+			ConditionStep{LeftValue: "{{status}}", Comparison: "equal", RightValue: "200", Steps: StepDefinitions{}},
+			`{"step_type":"condition","left_value":"{{status}}","comparison":"equal","right_value":"200","steps":[]}`,
+		},
+		{
+			SubtestStep{ID: "1", TestID: "2"},
+			`{"step_type":"subtest","id":"1","test_id":"2"}`,
+		},
+		{
+			GhostInspectorStep{IntegrationID: "1", SuiteID: "2"},
+			`{"step_type":"ghost-inspector","integration_id":"1","suite_id":"2","is_custom_start_url":false}`,
+		},
+		{
+			RequestStep{Method: "GET", URL: "https://yourapihere.com/"},
+			`{"step_type":"request","method":"GET","url":"https://yourapihere.com/","assertions":null,"variables":null,"scripts":null}`,
+		},
+	}
+
+	for _, test := range tests {
+		data, err := json.Marshal(test.definition)
+		if err != nil {
+			t.Errorf("Marshal(%+v) returned error: %v", test.definition, err)
+		}
+		if string(data) != test.want {
+			t.Errorf("Marshal(%+v): %s, want %s", test.definition, data, test.want)
+		}
+
+		definition, err := UnmarshalStepDefinition(data)
+		if err != nil {
+			t.Errorf("UnmarshalStepDefinition(%s) returned error: %v", data, err)
+		}
+		testResponseData(t, definition, test.definition)
+	}
+}
+
+func TestStepDefinitions(t *testing.T) {
+	data := `[
+  {"step_type": "request", "id": "1", "method": "POST", "url": "https://yourapihere.com/", "assertions": [{"source": "response_status", "comparison": "equal_number", "value": 200}]},
+  {"step_type": "pause", "id": "2", "duration": 3},
+  {"step_type": "condition", "id": "3", "left_value": "a", "comparison": "equal", "right_value": "a", "steps": [{"step_type": "pause", "duration": 1}]}
+]`
+
+	var definitions StepDefinitions
+	if err := json.Unmarshal([]byte(data), &definitions); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if len(definitions) != 3 {
+		t.Fatalf("Unmarshal returned %d steps, want 3", len(definitions))
+	}
+
+	request, ok := definitions[0].(RequestStep)
+	if !ok || request.Method != "POST" || len(request.Assertions) != 1 {
+		t.Errorf("First step: %#v", definitions[0])
+	}
+	if pause, ok := definitions[1].(PauseStep); !ok || pause.Duration != 3 {
+		t.Errorf("Second step: %#v", definitions[1])
+	}
+	condition, ok := definitions[2].(ConditionStep)
+	if !ok || len(condition.Steps) != 1 {
+		t.Fatalf("Third step: %#v", definitions[2])
+	}
+	if pause, ok := condition.Steps[0].(PauseStep); !ok || pause.Duration != 1 {
+		t.Errorf("Nested step: %#v", condition.Steps[0])
+	}
+
+	if err := json.Unmarshal([]byte(`[{"step_type": "teleport"}]`), &definitions); err == nil {
+		t.Error("Unmarshal should reject unknown step types")
+	}
+}
+
+func TestStepDefinitionConversion(t *testing.T) {
+	step := Step{StepType: "subtest", ID: "1", TestID: "2", Note: "run login"}
+	definition, err := step.Definition()
+	if err != nil {
+		t.Fatalf("Definition returned error: %v", err)
+	}
+	testResponseData(t, definition, SubtestStep{ID: "1", TestID: "2", Note: "run login"})
+	testResponseData(t, definition.ToStep(), step)
+	if definition.StepType() != StepTypeSubtest {
+		t.Errorf("StepType: %s, want %s", definition.StepType(), StepTypeSubtest)
+	}
+
+	step = Step{Method: "GET", URL: "https://example.com"}
+	definition, err = step.Definition()
+	if err != nil {
+		t.Fatalf("Definition without a step_type returned error: %v", err)
+	}
+	if request, ok := definition.(RequestStep); !ok || request.Method != "GET" || request.URL != "https://example.com" {
+		t.Errorf("Definition without a step_type returned %+v, want a GET request step", definition)
+	}
+
+	if _, err := (Step{}).Definition(); err != nil {
+		t.Errorf("Definition of an empty step returned error: %v", err)
+	}
+}
+
+func TestNewStepDefinition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var body string
+	mux.HandleFunc("/buckets/1/tests/1/steps", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		fmt.Fprint(w, `{"data": {"step_type": "pause", "id": "abc", "duration": 10}, "error": null, "meta": {"status": "success"}}`)
+	})
+
+	definition, err := client.NewStepDefinition("1", "1", PauseStep{Duration: 10})
+	if err != nil {
+		t.Fatalf("NewStepDefinition returned error: %v", err)
+	}
+	if body != `{"step_type":"pause","duration":10}` {
+		t.Errorf("Request body: %s", body)
+	}
+	testResponseData(t, definition, PauseStep{ID: "abc", Duration: 10})
+}
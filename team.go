@@ -1,6 +1,9 @@
 package runscope
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // Team represents a Runscope team
 type Team struct {
@@ -28,10 +31,15 @@ type TeamIntegration struct {
 
 // ListPeople returns a listing of people
 func (client *Client) ListPeople(teamID string) ([]Person, error) {
+	return client.ListPeopleWithContext(context.Background(), teamID)
+}
+
+// ListPeopleWithContext is the same as ListPeople, bound to the supplied context
+func (client *Client) ListPeopleWithContext(ctx context.Context, teamID string) ([]Person, error) {
 	var people = []Person{}
 
 	path := fmt.Sprintf("teams/%s/people", teamID)
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return people, err
 	}
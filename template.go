@@ -0,0 +1,303 @@
+package runscope
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRandomStringLength is the length of {{random_string}} values
+const DefaultRandomStringLength = 16
+
+const randomCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Template renders the {{variable}} references and built-in functions of
+// Runscope's template language, e.g. {{baseUrl}}/users/{{random_int}} or
+// {{encode_base64({{user}}:{{password}})}}
+type Template struct {
+	// Scopes are searched in order for the values of variables, so
+	// earlier scopes override later ones
+	Scopes []map[string]string
+	// Now returns the current time for {{timestamp}} and {{utc_datetime}}.
+	// When nil, time.Now is used.
+	Now func() time.Time
+}
+
+// NewTemplate creates a Template resolving variables from the given scopes
+func NewTemplate(scopes ...map[string]string) *Template {
+	return &Template{Scopes: scopes}
+}
+
+// NewEnvironmentTemplate creates a Template resolving variables from the
+// initial variables of an environment chain, e.g. a test environment
+// followed by the shared environment it inherits from
+func NewEnvironmentTemplate(environments ...Environment) *Template {
+	scopes := make([]map[string]string, len(environments))
+	for i, environment := range environments {
+		scopes[i] = environment.InitialVariables
+	}
+	return NewTemplate(scopes...)
+}
+
+// With returns a Template which resolves variables from the given scope
+// before the template's own scopes
+func (template *Template) With(scope map[string]string) *Template {
+	return &Template{
+		Scopes: append([]map[string]string{scope}, template.Scopes...),
+		Now:    template.Now,
+	}
+}
+
+// Lookup returns the value of a variable
+func (template *Template) Lookup(name string) (string, bool) {
+	for _, scope := range template.Scopes {
+		if value, ok := scope[name]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Render resolves every reference in s. References to unknown variables
+// and functions are left as they are.
+func (template *Template) Render(s string) string {
+	rendered, _ := template.render(s)
+	return rendered
+}
+
+// Unresolved returns the references in s which cannot be resolved, in
+// the order they first appear
+func (template *Template) Unresolved(s string) []string {
+	_, unresolved := template.render(s)
+	return unresolved
+}
+
+// RenderStep resolves the references in the request, assertions and
+// variables of a request step, or the values of a condition step. The
+// nested steps of a condition are not rendered. The unresolved references
+// are returned with the rendered step.
+func (template *Template) RenderStep(step Step) (Step, []string) {
+	var unresolved []string
+	render := func(s string) string {
+		rendered, references := template.render(s)
+		unresolved = appendUnique(unresolved, references...)
+		return rendered
+	}
+	renderValues := func(values map[string][]string) map[string][]string {
+		if values == nil {
+			return nil
+		}
+		rendered := make(map[string][]string, len(values))
+		for name, list := range values {
+			for _, value := range list {
+				rendered[name] = append(rendered[name], render(value))
+			}
+		}
+		return rendered
+	}
+
+	step.URL = render(step.URL)
+	step.Body = render(step.Body)
+	step.Headers = renderValues(step.Headers)
+	step.Form = renderValues(step.Form)
+	step.Auth.Username = render(step.Auth.Username)
+	step.Auth.Password = render(step.Auth.Password)
+	step.Auth.AccessToken = render(step.Auth.AccessToken)
+	step.Auth.TokenSecret = render(step.Auth.TokenSecret)
+	step.Auth.ConsumerKey = render(step.Auth.ConsumerKey)
+	step.Auth.ConsumerSecret = render(step.Auth.ConsumerSecret)
+	step.LeftValue = render(step.LeftValue)
+	step.RightValue = render(step.RightValue)
+
+	assertions := make([]Assertion, len(step.Assertions))
+	for i, assertion := range step.Assertions {
+		assertion.Property = render(assertion.Property)
+		if value, ok := assertion.Value.(string); ok {
+			assertion.Value = render(value)
+		}
+		assertions[i] = assertion
+	}
+	step.Assertions = assertions
+
+	variables := make([]Variable, len(step.Variables))
+	for i, variable := range step.Variables {
+		variable.Property = render(variable.Property)
+		variables[i] = variable
+	}
+	step.Variables = variables
+
+	return step, unresolved
+}
+
+// render resolves the references in s, innermost first
+func (template *Template) render(s string) (string, []string) {
+	var unresolved []string
+	var rendered strings.Builder
+
+	for {
+		start := strings.Index(s, "{{")
+		if start < 0 {
+			break
+		}
+		end := closingBraces(s, start)
+		if end < 0 {
+			break
+		}
+
+		inner, references := template.render(s[start+2 : end])
+		unresolved = appendUnique(unresolved, references...)
+
+		// an expression with unresolved arguments is left as it is,
+		// reporting only the arguments
+		rendered.WriteString(s[:start])
+		if len(references) > 0 {
+			rendered.WriteString("{{" + inner + "}}")
+		} else if value, ok := template.evaluate(strings.TrimSpace(inner)); ok {
+			rendered.WriteString(value)
+		} else {
+			unresolved = appendUnique(unresolved, strings.TrimSpace(inner))
+			rendered.WriteString("{{" + inner + "}}")
+		}
+		s = s[end+2:]
+	}
+
+	rendered.WriteString(s)
+	return rendered.String(), unresolved
+}
+
+// closingBraces returns the index of the }} matching the {{ at start
+func closingBraces(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s)-1; i++ {
+		switch s[i : i+2] {
+		case "{{":
+			depth++
+			i++
+		case "}}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+			i++
+		}
+	}
+	return -1
+}
+
+var templateCall = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(?:\((.*)\))?$`)
+
+// evaluate resolves a single expression, which is either a variable or a
+// call to a built-in function
+func (template *Template) evaluate(expression string) (string, bool) {
+	match := templateCall.FindStringSubmatch(expression)
+	if match == nil {
+		return "", false
+	}
+
+	name := match[1]
+	if !strings.Contains(expression, "(") {
+		if value, ok := template.Lookup(name); ok {
+			return value, true
+		}
+	}
+
+	var args []string
+	if match[2] != "" {
+		for _, arg := range strings.Split(match[2], ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+	}
+	return template.call(name, args, match[2])
+}
+
+// call evaluates a built-in function. raw is the unsplit argument list,
+// used by functions that take a single value which may contain commas.
+func (template *Template) call(name string, args []string, raw string) (string, bool) {
+	switch name {
+	case "random_int":
+		switch len(args) {
+		case 0:
+			return strconv.FormatInt(rand.Int63(), 10), true
+		case 2:
+			min, err1 := strconv.ParseInt(args[0], 10, 64)
+			max, err2 := strconv.ParseInt(args[1], 10, 64)
+			if err1 != nil || err2 != nil || max < min {
+				return "", false
+			}
+			return strconv.FormatInt(min+rand.Int63n(max-min+1), 10), true
+		}
+	case "random_string":
+		length := DefaultRandomStringLength
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 0 {
+				return "", false
+			}
+			length = n
+		} else if len(args) > 1 {
+			return "", false
+		}
+		return randomString(length), true
+	case "random_email":
+		if len(args) == 0 {
+			return randomString(DefaultRandomStringLength) + "@example.com", true
+		}
+	case "uuid":
+		if len(args) == 0 {
+			return newUUID(), true
+		}
+	case "timestamp":
+		if len(args) == 0 {
+			return strconv.FormatInt(template.now().Unix(), 10), true
+		}
+	case "utc_datetime":
+		if len(args) == 0 {
+			return template.now().UTC().Format(time.RFC3339), true
+		}
+	case "encode_base64":
+		return base64.StdEncoding.EncodeToString([]byte(raw)), true
+	case "encode_url":
+		return url.QueryEscape(raw), true
+	case "md5":
+		sum := md5.Sum([]byte(raw))
+		return hex.EncodeToString(sum[:]), true
+	}
+	return "", false
+}
+
+func (template *Template) now() time.Time {
+	if template.Now != nil {
+		return template.Now()
+	}
+	return time.Now()
+}
+
+func randomString(length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = randomCharacters[rand.Intn(len(randomCharacters))]
+	}
+	return string(b)
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
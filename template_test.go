@@ -0,0 +1,98 @@
+package runscope
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestTemplateRender(t *testing.T) {
+	template := NewEnvironmentTemplate(
+		Environment{InitialVariables: map[string]string{"baseUrl": "http://localhost:8080", "user": "grace"}},
+		Environment{InitialVariables: map[string]string{"baseUrl": "https://api.example.com", "password": "secret"}},
+	)
+	template.Now = func() time.Time { return time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"{{baseUrl}}/users", "http://localhost:8080/users"},
+		{"{{ user }}", "grace"},
+		{"no references", "no references"},
+		{"{{timestamp}}", "1493640000"},
+		{"{{utc_datetime}}", "2017-05-01T12:00:00Z"},
+		{"Basic {{encode_base64({{user}}:{{password}})}}", "Basic Z3JhY2U6c2VjcmV0"},
+		{"q={{encode_url(a b&c)}}", "q=a+b%26c"},
+		{"{{md5(grace)}}", "15e5c87b18c1289d45bb4a72961b58e8"},
+		{"{{random_int(5, 5)}}", "5"},
+		{"{{unclosed", "{{unclosed"},
+	}
+
+	for _, test := range tests {
+		if got := template.Render(test.input); got != test.want {
+			t.Errorf("Render(%q) returned %q, expected %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestTemplateRandom(t *testing.T) {
+	template := NewTemplate()
+
+	patterns := map[string]string{
+		"{{random_string}}":    `^[A-Za-z0-9]{16}$`,
+		"{{random_string(8)}}": `^[A-Za-z0-9]{8}$`,
+		"{{random_int}}":       `^\d+$`,
+		"{{random_email}}":     `^[A-Za-z0-9]{16}@example\.com$`,
+		"{{uuid}}":             `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+	}
+	for input, pattern := range patterns {
+		if got := template.Render(input); !regexp.MustCompile(pattern).MatchString(got) {
+			t.Errorf("Render(%q) returned %q, expected a match for %s", input, got, pattern)
+		}
+	}
+}
+
+func TestTemplateUnresolved(t *testing.T) {
+	template := NewTemplate(map[string]string{"user": "grace"})
+
+	input := "{{baseUrl}}/{{user}}/{{encode_base64({{token}})}}/{{baseUrl}}/{{unknown_function(1)}}"
+	want := []string{"baseUrl", "token", "unknown_function(1)"}
+	if got := template.Unresolved(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unresolved returned %v, expected %v", got, want)
+	}
+
+	if got := template.Render(input); got != "{{baseUrl}}/grace/{{encode_base64({{token}})}}/{{baseUrl}}/{{unknown_function(1)}}" {
+		t.Errorf("Render left unexpected output %q", got)
+	}
+}
+
+func TestTemplateRenderStep(t *testing.T) {
+	template := NewTemplate(map[string]string{"id": "7"}).With(map[string]string{"token": "abc"})
+
+	step := Step{
+		URL:        "{{baseUrl}}/users/{{id}}",
+		Headers:    map[string][]string{"Authorization": {"Bearer {{token}}"}},
+		Form:       map[string][]string{"id": {"{{id}}"}},
+		Auth:       Auth{AuthType: "basic", Username: "{{id}}", Password: "{{password}}"},
+		Assertions: []Assertion{AssertJSON("id").Equals("{{id}}")},
+		Variables:  []Variable{VariableFromJSON("name", "users.{{id}}.name")},
+	}
+
+	rendered, unresolved := template.RenderStep(step)
+	if !reflect.DeepEqual(unresolved, []string{"baseUrl", "password"}) {
+		t.Errorf("unexpected unresolved references %v", unresolved)
+	}
+	if rendered.URL != "{{baseUrl}}/users/7" ||
+		rendered.Headers["Authorization"][0] != "Bearer abc" ||
+		rendered.Form["id"][0] != "7" ||
+		rendered.Auth.Username != "7" ||
+		rendered.Assertions[0].Value != "7" ||
+		rendered.Variables[0].Property != "users.7.name" {
+		t.Errorf("unexpected rendered step %+v", rendered)
+	}
+	if step.Headers["Authorization"][0] != "Bearer {{token}}" || step.Assertions[0].Value != "{{id}}" {
+		t.Errorf("RenderStep modified the original step")
+	}
+}
@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -97,6 +98,11 @@ type ListTestOptions struct {
 
 // ListTests returns a slice of Tests for a given bucket
 func (client *Client) ListTests(bucketKey string, options ListTestOptions) ([]Test, error) {
+	return client.ListTestsWithContext(context.Background(), bucketKey, options)
+}
+
+// ListTestsWithContext is the same as ListTests, bound to the supplied context
+func (client *Client) ListTestsWithContext(ctx context.Context, bucketKey string, options ListTestOptions) ([]Test, error) {
 	var tests = []Test{}
 
 	path := fmt.Sprintf("buckets/%s/tests", bucketKey)
@@ -105,7 +111,7 @@ func (client *Client) ListTests(bucketKey string, options ListTestOptions) ([]Te
 		path = fmt.Sprintf("%s?count=%d&offset=%d", path, options.Count, options.Offset)
 	}
 
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return tests, err
 	}
@@ -116,36 +122,26 @@ func (client *Client) ListTests(bucketKey string, options ListTestOptions) ([]Te
 
 // ListAllTests returns all tests for a given bucket
 func (client *Client) ListAllTests(bucketKey string) ([]Test, error) {
-	var tests = []Test{}
-	count := 50
-	offset := 0
-
-	for {
-		t, err := client.ListTests(bucketKey, ListTestOptions{
-			Count:  count,
-			Offset: offset,
-		})
-		if err != nil {
-			return tests, err
-		}
-
-		tests = append(tests, t...)
-
-		if len(t) < count {
-			return tests, nil
-		}
+	return client.ListAllTestsWithContext(context.Background(), bucketKey)
+}
 
-		offset += 50
-	}
+// ListAllTestsWithContext is the same as ListAllTests, bound to the supplied context
+func (client *Client) ListAllTestsWithContext(ctx context.Context, bucketKey string) ([]Test, error) {
+	return client.IterateTests(ctx, bucketKey, DefaultPageSize).All()
 }
 
 // GetTest returns details about a given test
 func (client *Client) GetTest(bucketKey string, testID string) (Test, error) {
+	return client.GetTestWithContext(context.Background(), bucketKey, testID)
+}
+
+// GetTestWithContext is the same as GetTest, bound to the supplied context
+func (client *Client) GetTestWithContext(ctx context.Context, bucketKey string, testID string) (Test, error) {
 	var test = Test{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s", bucketKey, testID)
 
-	content, err := client.Get(path)
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return test, err
 	}
@@ -156,6 +152,11 @@ func (client *Client) GetTest(bucketKey string, testID string) (Test, error) {
 
 // NewTest creates a new test in a given bucket
 func (client *Client) NewTest(bucketKey string, newTestRequest NewTestRequest) (Test, error) {
+	return client.NewTestWithContext(context.Background(), bucketKey, newTestRequest)
+}
+
+// NewTestWithContext is the same as NewTest, bound to the supplied context
+func (client *Client) NewTestWithContext(ctx context.Context, bucketKey string, newTestRequest NewTestRequest) (Test, error) {
 	var test = Test{}
 
 	path := fmt.Sprintf("buckets/%s/tests", bucketKey)
@@ -164,7 +165,7 @@ func (client *Client) NewTest(bucketKey string, newTestRequest NewTestRequest) (
 		return test, err
 	}
 
-	content, err := client.Post(path, data)
+	content, err := client.PostWithContext(ctx, path, data)
 	if err != nil {
 		return test, err
 	}
@@ -175,6 +176,11 @@ func (client *Client) NewTest(bucketKey string, newTestRequest NewTestRequest) (
 
 // UpdateTest modifies an existing test in a given bucket
 func (client *Client) UpdateTest(bucketKey string, testID string, updateTestRequest UpdateTestRequest) (Test, error) {
+	return client.UpdateTestWithContext(context.Background(), bucketKey, testID, updateTestRequest)
+}
+
+// UpdateTestWithContext is the same as UpdateTest, bound to the supplied context
+func (client *Client) UpdateTestWithContext(ctx context.Context, bucketKey string, testID string, updateTestRequest UpdateTestRequest) (Test, error) {
 	var test = Test{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s", bucketKey, testID)
@@ -183,7 +189,7 @@ func (client *Client) UpdateTest(bucketKey string, testID string, updateTestRequ
 		return test, err
 	}
 
-	content, err := client.Put(path, data)
+	content, err := client.PutWithContext(ctx, path, data)
 	if err != nil {
 		return test, err
 	}
@@ -194,11 +200,16 @@ func (client *Client) UpdateTest(bucketKey string, testID string, updateTestRequ
 
 // ImportTest creates a test for a given bucket with a JSON payload
 func (client *Client) ImportTest(bucketKey string, data []byte) (Test, error) {
+	return client.ImportTestWithContext(context.Background(), bucketKey, data)
+}
+
+// ImportTestWithContext is the same as ImportTest, bound to the supplied context
+func (client *Client) ImportTestWithContext(ctx context.Context, bucketKey string, data []byte) (Test, error) {
 	var test = Test{}
 
 	path := fmt.Sprintf("buckets/%s/tests", bucketKey)
 
-	content, err := client.Post(path, data)
+	content, err := client.PostWithContext(ctx, path, data)
 	if err != nil {
 		return test, err
 	}
@@ -209,11 +220,16 @@ func (client *Client) ImportTest(bucketKey string, data []byte) (Test, error) {
 
 // ReimportTest updates an existing test for a given bucket with a JSON payload
 func (client *Client) ReimportTest(bucketKey string, testID string, data []byte) (Test, error) {
+	return client.ReimportTestWithContext(context.Background(), bucketKey, testID, data)
+}
+
+// ReimportTestWithContext is the same as ReimportTest, bound to the supplied context
+func (client *Client) ReimportTestWithContext(ctx context.Context, bucketKey string, testID string, data []byte) (Test, error) {
 	var test = Test{}
 
 	path := fmt.Sprintf("buckets/%s/tests/%s", bucketKey, testID)
 
-	content, err := client.Put(path, data)
+	content, err := client.PutWithContext(ctx, path, data)
 	if err != nil {
 		return test, err
 	}
@@ -224,8 +240,13 @@ func (client *Client) ReimportTest(bucketKey string, testID string, data []byte)
 
 // DeleteTest removes a test from a bucket
 func (client *Client) DeleteTest(bucketKey string, testID string) error {
+	return client.DeleteTestWithContext(context.Background(), bucketKey, testID)
+}
+
+// DeleteTestWithContext is the same as DeleteTest, bound to the supplied context
+func (client *Client) DeleteTestWithContext(ctx context.Context, bucketKey string, testID string) error {
 	path := fmt.Sprintf("buckets/%s/tests/%s", bucketKey, testID)
-	return client.Delete(path)
+	return client.DeleteWithContext(ctx, path)
 }
 
 // Trigger starts one or more test runs.
@@ -236,11 +257,23 @@ func (client *Client) DeleteTest(bucketKey string, testID string) error {
 // - https://api.runscope.com/radar/:trigger_id/trigger?baseUrl=https://yourapihere.com&apiKey=abc123
 // - https://api.runscope.com/radar/:trigger_id/trigger?runscope_environment=:environment_uuid
 // - https://api.runscope.com/radar/bucket/:trigger_id/trigger
+//
+// The url must belong to the client's base URL. See TriggerRequest for
+// building trigger URLs.
 func (client *Client) Trigger(url string) (TriggerResult, error) {
+	return client.TriggerWithContext(context.Background(), url)
+}
+
+// TriggerWithContext is the same as Trigger, bound to the supplied context
+func (client *Client) TriggerWithContext(ctx context.Context, url string) (TriggerResult, error) {
 	var result = TriggerResult{}
 
-	path := url[len(client.baseURL):]
-	content, err := client.Get(path)
+	path, err := client.relativePath(url)
+	if err != nil {
+		return result, err
+	}
+
+	content, err := client.GetWithContext(ctx, path)
 	if err != nil {
 		return result, err
 	}
@@ -0,0 +1,295 @@
+package runscope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TriggerRequest describes a test run started with a trigger, and builds
+// the trigger URL with its parameters properly escaped
+type TriggerRequest struct {
+	// TriggerID is the trigger ID of a test, or of a bucket when Bucket
+	// is set, as found at the end of Test.TriggerURL or Bucket.TriggerURL
+	TriggerID string
+	Bucket    bool
+	// EnvironmentID runs the test in the given environment instead of
+	// the test's default environment
+	EnvironmentID string
+	// Regions runs the test in the given regions instead of those of
+	// the environment
+	Regions []string
+	// Notify enables or disables notifications for the runs. The
+	// environment settings apply when it is nil.
+	Notify *bool
+	// BaseURL sets the baseUrl initial variable
+	BaseURL string
+	// Variables are additional initial variables for the runs
+	Variables map[string]string
+	// JSONBody sends the initial variables as a JSON body with a POST
+	// request instead of as query parameters
+	JSONBody bool
+}
+
+// variables returns every initial variable of the request
+func (request TriggerRequest) variables() map[string]string {
+	variables := map[string]string{}
+	for name, value := range request.Variables {
+		variables[name] = value
+	}
+	if request.BaseURL != "" {
+		variables["baseUrl"] = request.BaseURL
+	}
+	return variables
+}
+
+// path returns the trigger path relative to the API base URL
+func (request TriggerRequest) path() (string, error) {
+	if request.TriggerID == "" {
+		return "", errors.New("TriggerID must not be empty when triggering tests")
+	}
+
+	path := "radar/" + url.PathEscape(request.TriggerID) + "/trigger"
+	if request.Bucket {
+		path = "radar/bucket/" + url.PathEscape(request.TriggerID) + "/trigger"
+	}
+
+	qs := url.Values{}
+	if request.EnvironmentID != "" {
+		qs.Set("runscope_environment", request.EnvironmentID)
+	}
+	for _, region := range request.Regions {
+		qs.Add("runscope_region", region)
+	}
+	if request.Notify != nil {
+		qs.Set("runscope_notify", fmt.Sprint(*request.Notify))
+	}
+	if !request.JSONBody {
+		for name, value := range request.variables() {
+			qs.Set(name, value)
+		}
+	}
+
+	if len(qs) > 0 {
+		path += "?" + qs.Encode()
+	}
+	return path, nil
+}
+
+// TriggerURL returns the absolute trigger URL for a request
+func (client *Client) TriggerURL(request TriggerRequest) (string, error) {
+	path, err := request.path()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", client.baseURL, path), nil
+}
+
+// TriggerTests starts one or more test runs as described by a TriggerRequest
+func (client *Client) TriggerTests(request TriggerRequest) (TriggerResult, error) {
+	return client.TriggerTestsWithContext(context.Background(), request)
+}
+
+// TriggerTestsWithContext is the same as TriggerTests, bound to the supplied context
+func (client *Client) TriggerTestsWithContext(ctx context.Context, request TriggerRequest) (TriggerResult, error) {
+	var result = TriggerResult{}
+
+	path, err := request.path()
+	if err != nil {
+		return result, err
+	}
+
+	var content []byte
+	if request.JSONBody {
+		data, err := json.Marshal(request.variables())
+		if err != nil {
+			return result, err
+		}
+		content, err = client.PostWithContext(ctx, path, data)
+	} else {
+		content, err = client.GetWithContext(ctx, path)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	err = unmarshal(content, &result)
+	return result, err
+}
+
+// ParseTriggerURL returns the TriggerRequest for a trigger URL such as
+// Test.TriggerURL or Bucket.TriggerURL
+func ParseTriggerURL(rawURL string) (TriggerRequest, error) {
+	var request = TriggerRequest{}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return request, err
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	n := len(segments)
+	if n < 3 || segments[n-1] != "trigger" {
+		return request, fmt.Errorf("%s is not a trigger URL", rawURL)
+	}
+	switch {
+	case n >= 4 && segments[n-4] == "radar" && segments[n-3] == "bucket":
+		request.Bucket = true
+	case segments[n-3] != "radar":
+		return request, fmt.Errorf("%s is not a trigger URL", rawURL)
+	}
+	request.TriggerID = segments[n-2]
+
+	for name, values := range u.Query() {
+		switch name {
+		case "runscope_environment":
+			request.EnvironmentID = values[0]
+		case "runscope_region":
+			request.Regions = values
+		case "runscope_notify":
+			notify := values[0] == "true"
+			request.Notify = &notify
+		case "baseUrl":
+			request.BaseURL = values[0]
+		default:
+			if request.Variables == nil {
+				request.Variables = map[string]string{}
+			}
+			request.Variables[name] = values[0]
+		}
+	}
+	return request, nil
+}
+
+// relativePath returns the path of an absolute URL relative to the
+// client's base URL, or an error if the URL belongs to another host
+func (client *Client) relativePath(rawURL string) (string, error) {
+	base, err := url.Parse(client.baseURL)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	basePath := strings.TrimSuffix(base.EscapedPath(), "/")
+	path := u.EscapedPath()
+	if u.Host != base.Host || !strings.HasPrefix(path, basePath+"/") {
+		return "", fmt.Errorf("%s does not belong to the API at %s", rawURL, client.baseURL)
+	}
+
+	path = path[len(basePath)+1:]
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path, nil
+}
+
+// WaitOptions control how TriggerAndWait polls for results
+type WaitOptions struct {
+	// Interval is the delay before the first poll. Defaults to 5 seconds.
+	Interval time.Duration
+	// MaxInterval caps the delay between polls, which doubles after
+	// every poll while runs are unfinished. Defaults to 30 seconds.
+	MaxInterval time.Duration
+}
+
+// WaitResult is the outcome of runs started by a trigger
+type WaitResult struct {
+	Trigger TriggerResult
+	// Results holds the final result of each run, in the order of
+	// Trigger.Runs
+	Results []Result
+	// Passed is true when at least one run was started, every run passed
+	// and no run failed to start
+	Passed bool
+}
+
+// pendingResults are the states of a result whose run has not finished
+var pendingResults = map[string]bool{
+	"":        true,
+	"init":    true,
+	"queued":  true,
+	"working": true,
+}
+
+// TriggerAndWait starts test runs with a trigger URL (see Trigger) and
+// blocks until every run has finished or the context is done
+func (client *Client) TriggerAndWait(ctx context.Context, url string, options WaitOptions) (WaitResult, error) {
+	trigger, err := client.TriggerWithContext(ctx, url)
+	if err != nil {
+		return WaitResult{Trigger: trigger}, err
+	}
+	return client.WaitForRuns(ctx, trigger, options)
+}
+
+// WaitForRuns polls the results of every run started by a trigger until
+// each has finished or the context is done
+func (client *Client) WaitForRuns(ctx context.Context, trigger TriggerResult, options WaitOptions) (WaitResult, error) {
+	if options.Interval <= 0 {
+		options.Interval = 5 * time.Second
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = 30 * time.Second
+	}
+	if options.MaxInterval < options.Interval {
+		options.MaxInterval = options.Interval
+	}
+
+	wait := WaitResult{
+		Trigger: trigger,
+		Results: make([]Result, len(trigger.Runs)),
+	}
+	done := make([]bool, len(trigger.Runs))
+	remaining := len(trigger.Runs)
+	interval := options.Interval
+
+	for remaining > 0 {
+		if err := sleep(ctx, interval); err != nil {
+			return wait, err
+		}
+
+		for i, run := range trigger.Runs {
+			if done[i] {
+				continue
+			}
+
+			result, err := client.GetResultWithContext(ctx, run.BucketKey, run.TestID, run.TestRunID)
+			if IsNotFound(err) {
+				// the run has not been registered yet
+				continue
+			}
+			if err != nil && ctx.Err() != nil {
+				// report the deadline rather than the request it cut short
+				return wait, ctx.Err()
+			}
+			if err != nil {
+				return wait, err
+			}
+
+			wait.Results[i] = result
+			if !pendingResults[result.Result] {
+				done[i] = true
+				remaining--
+			}
+		}
+
+		interval *= 2
+		if interval > options.MaxInterval {
+			interval = options.MaxInterval
+		}
+	}
+
+	wait.Passed = len(trigger.Runs) > 0 && trigger.RunsFailed == 0
+	for _, result := range wait.Results {
+		if result.Result != "pass" {
+			wait.Passed = false
+		}
+	}
+	return wait, nil
+}
@@ -0,0 +1,235 @@
+package runscope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const triggerResponse = `
+{
+  "data": {
+    "runs": [
+      {
+        "bucket_key": "1",
+        "test_id": "1",
+        "test_run_id": "a"
+      },
+      {
+        "bucket_key": "1",
+        "test_id": "2",
+        "test_run_id": "b"
+      }
+    ],
+    "runs_failed": 0,
+    "runs_started": 2,
+    "runs_total": 2
+  },
+  "error": null,
+  "meta": {
+    "status": "success"
+  }
+}`
+
+// handleRunResult serves a result which moves through the given states,
+// one per poll, staying in the last one
+func handleRunResult(path string, testRunID string, states ...string) *int {
+	polls := 0
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		state := states[len(states)-1]
+		if polls < len(states) {
+			state = states[polls]
+		}
+		polls++
+		if state == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"test_run_id": %q, "result": %q}, "error": null, "meta": {"status": "success"}}`, testRunID, state)
+	})
+	return &polls
+}
+
+func TestTriggerAndWait(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleGet(t, "/radar/abc/trigger", http.StatusCreated, triggerResponse)
+	first := handleRunResult("/buckets/1/tests/1/results/a", "a", "missing", "queued", "working", "pass")
+	second := handleRunResult("/buckets/1/tests/2/results/b", "b", "pass")
+
+	wait, err := client.TriggerAndWait(context.Background(), server.URL+"/radar/abc/trigger", WaitOptions{
+		Interval:    time.Millisecond,
+		MaxInterval: 2 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAndWait returned error: %v", err)
+	}
+	if !wait.Passed {
+		t.Error("TriggerAndWait should pass when every run passes")
+	}
+	if len(wait.Results) != 2 || wait.Results[0].TestRunID != "a" || wait.Results[1].TestRunID != "b" {
+		t.Errorf("TriggerAndWait returned results %+v", wait.Results)
+	}
+	if *first != 4 || *second != 1 {
+		t.Errorf("Polls: %d and %d, want 4 and 1", *first, *second)
+	}
+}
+
+func TestTriggerAndWaitFailed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleGet(t, "/radar/abc/trigger", http.StatusCreated, triggerResponse)
+	handleRunResult("/buckets/1/tests/1/results/a", "a", "working", "fail")
+	handleRunResult("/buckets/1/tests/2/results/b", "b", "pass")
+
+	wait, err := client.TriggerAndWait(context.Background(), server.URL+"/radar/abc/trigger", WaitOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("TriggerAndWait returned error: %v", err)
+	}
+	if wait.Passed {
+		t.Error("TriggerAndWait should fail when a run fails")
+	}
+	if wait.Results[0].Result != "fail" {
+		t.Errorf("Result: %v, want %v", wait.Results[0].Result, "fail")
+	}
+}
+
+func TestWaitForRunsNoRuns(t *testing.T) {
+	setup()
+	defer teardown()
+
+	wait, err := client.WaitForRuns(context.Background(), TriggerResult{}, WaitOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("WaitForRuns returned error: %v", err)
+	}
+	if wait.Passed {
+		t.Error("WaitForRuns should not pass when no runs were started")
+	}
+}
+
+func TestTriggerAndWaitTimeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleGet(t, "/radar/abc/trigger", http.StatusCreated, triggerResponse)
+	handleRunResult("/buckets/1/tests/1/results/a", "a", "working")
+	handleRunResult("/buckets/1/tests/2/results/b", "b", "pass")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	wait, err := client.TriggerAndWait(ctx, server.URL+"/radar/abc/trigger", WaitOptions{Interval: time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TriggerAndWait returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if wait.Results[0].Result != "working" || wait.Results[1].Result != "pass" {
+		t.Errorf("TriggerAndWait should return the results seen so far, got %+v", wait.Results)
+	}
+}
+
+func TestTriggerURL(t *testing.T) {
+	client := NewClient(Options{BaseURL: "https://api.runscope.com"})
+	notify := false
+
+	tests := []struct {
+		request TriggerRequest
+		want    string
+	}{
+		{
+			TriggerRequest{TriggerID: "abc"},
+			"https://api.runscope.com/radar/abc/trigger",
+		},
+		{
+			TriggerRequest{TriggerID: "abc", Bucket: true, Regions: []string{"us1", "eu1"}},
+			"https://api.runscope.com/radar/bucket/abc/trigger?runscope_region=us1&runscope_region=eu1",
+		},
+		{
+			TriggerRequest{
+				TriggerID:     "abc",
+				EnvironmentID: "def",
+				Notify:        &notify,
+				BaseURL:       "https://staging.yourapihere.com",
+				Variables:     map[string]string{"user": "grace hopper&co"},
+			},
+			"https://api.runscope.com/radar/abc/trigger?baseUrl=https%3A%2F%2Fstaging.yourapihere.com&runscope_environment=def&runscope_notify=false&user=grace+hopper%26co",
+		},
+		{
+			TriggerRequest{TriggerID: "abc", JSONBody: true, Variables: map[string]string{"user": "grace"}},
+			"https://api.runscope.com/radar/abc/trigger",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := client.TriggerURL(test.request)
+		if err != nil {
+			t.Errorf("TriggerURL(%+v) returned error: %v", test.request, err)
+		}
+		if got != test.want {
+			t.Errorf("TriggerURL(%+v): %s, want %s", test.request, got, test.want)
+		}
+
+		parsed, err := ParseTriggerURL(got)
+		if err != nil {
+			t.Errorf("ParseTriggerURL(%s) returned error: %v", got, err)
+		}
+		if !test.request.JSONBody {
+			testResponseData(t, parsed, test.request)
+		}
+	}
+
+	if _, err := client.TriggerURL(TriggerRequest{}); err == nil {
+		t.Error("TriggerURL should require a TriggerID")
+	}
+	if _, err := ParseTriggerURL("https://api.runscope.com/buckets/abc"); err == nil {
+		t.Error("ParseTriggerURL should reject URLs which are not triggers")
+	}
+}
+
+func TestTriggerTests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var query, body string
+	mux.HandleFunc("/radar/abc/trigger", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		fmt.Fprint(w, triggerResponse)
+	})
+
+	result, err := client.TriggerTests(TriggerRequest{TriggerID: "abc", Regions: []string{"us1"}, Variables: map[string]string{"user": "grace"}})
+	if err != nil {
+		t.Fatalf("TriggerTests returned error: %v", err)
+	}
+	if result.RunsStarted != 2 {
+		t.Errorf("RunsStarted: %d, want %d", result.RunsStarted, 2)
+	}
+	if query != "runscope_region=us1&user=grace" || body != "" {
+		t.Errorf("Trigger request query %q and body %q", query, body)
+	}
+
+	_, err = client.TriggerTests(TriggerRequest{TriggerID: "abc", JSONBody: true, BaseURL: "https://yourapihere.com", Variables: map[string]string{"user": "grace"}})
+	if err != nil {
+		t.Fatalf("TriggerTests returned error: %v", err)
+	}
+	if query != "" || body != `{"baseUrl":"https://yourapihere.com","user":"grace"}` {
+		t.Errorf("Trigger request query %q and body %q", query, body)
+	}
+}
+
+func TestTriggerForeignURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, url := range []string{"", "/", "https://example.com/radar/abc/trigger", "%zz"} {
+		if _, err := client.Trigger(url); err == nil {
+			t.Errorf("Trigger(%q) should return an error", url)
+		}
+	}
+}
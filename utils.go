@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -13,7 +14,12 @@ func unmarshal(content []byte, result interface{}) error {
 	}
 
 	if response.Error.Message != "" {
-		return fmt.Errorf("%s (%d): %+v", response.Error.Message, response.Error.Status, response.Meta)
+		return &APIError{
+			ErrorStatus: response.Error.Status,
+			Message:     response.Error.Message,
+			MetaStatus:  response.Meta.Status,
+			Body:        content,
+		}
 	}
 	return nil
 }
@@ -21,3 +27,17 @@ func unmarshal(content []byte, result interface{}) error {
 func unixTimestampToFloat(t time.Time) float64 {
 	return float64(t.UnixNano()) / 1.0e9
 }
+
+func floatToTime(timestamp float64) time.Time {
+	seconds := int64(timestamp)
+	return time.Unix(seconds, int64((timestamp-float64(seconds))*1.0e9))
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,106 @@
+package runscope
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// variableName matches the names Runscope accepts for variables
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// variableSources are the sources variables can be extracted from,
+// mapped to whether a property is required
+var variableSources = map[Source]bool{
+	SourceJSON:    true,
+	SourceXML:     true,
+	SourceHeaders: true,
+	SourceCookies: true,
+	SourceText:    true,
+	SourceStatus:  false,
+}
+
+// VariableFromJSON extracts a variable from a property of a JSON
+// response body, e.g. "data.users[0].id"
+func VariableFromJSON(name string, path string) Variable {
+	return Variable{Name: name, Source: string(SourceJSON), Property: path}
+}
+
+// VariableFromXML extracts a variable from an XPath expression of an
+// XML response body
+func VariableFromXML(name string, xpath string) Variable {
+	return Variable{Name: name, Source: string(SourceXML), Property: xpath}
+}
+
+// VariableFromHeader extracts a variable from a response header
+func VariableFromHeader(name string, header string) Variable {
+	return Variable{Name: name, Source: string(SourceHeaders), Property: header}
+}
+
+// VariableFromStatus extracts a variable from the response status code
+func VariableFromStatus(name string) Variable {
+	return Variable{Name: name, Source: string(SourceStatus)}
+}
+
+// VariableFromCookie extracts a variable from a cookie set by the response
+func VariableFromCookie(name string, cookie string) Variable {
+	return Variable{Name: name, Source: string(SourceCookies), Property: cookie}
+}
+
+// VariableFromText extracts a variable from the raw response body with a
+// regular expression. The first capture group is used when there is one.
+func VariableFromText(name string, pattern string) Variable {
+	return Variable{Name: name, Source: string(SourceText), Property: pattern}
+}
+
+// ValidateVariable checks that a variable has a valid name and a
+// property when its source requires one, as enforced by the Runscope
+// editor
+func ValidateVariable(variable Variable) error {
+	if !variableName.MatchString(variable.Name) {
+		return fmt.Errorf("Variable name %q must start with a letter or underscore and contain only letters, numbers and underscores", variable.Name)
+	}
+
+	source := Source(variable.Source)
+	required, ok := variableSources[source]
+	if !ok {
+		return fmt.Errorf("Variable %s has unsupported source %q", variable.Name, variable.Source)
+	}
+	if required && variable.Property == "" {
+		return fmt.Errorf("Variable %s requires a property for source %q", variable.Name, variable.Source)
+	}
+	if !required && variable.Property != "" {
+		return fmt.Errorf("Variable %s source %q does not take a property", variable.Name, variable.Source)
+	}
+	if source == SourceText {
+		if _, err := regexp.Compile(variable.Property); err != nil {
+			return fmt.Errorf("Variable %s has an invalid pattern: %v", variable.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateStep checks every assertion and variable of a step and its
+// nested steps before it is sent to Runscope
+func validateStep(step Step) error {
+	for i, assertion := range step.Assertions {
+		if err := ValidateAssertion(assertion); err != nil {
+			return fmt.Errorf("Step assertion %d: %v", i, err)
+		}
+	}
+	names := map[string]bool{}
+	for i, variable := range step.Variables {
+		if err := ValidateVariable(variable); err != nil {
+			return fmt.Errorf("Step variable %d: %v", i, err)
+		}
+		if names[variable.Name] {
+			return fmt.Errorf("Step variable %d: %s is extracted more than once", i, variable.Name)
+		}
+		names[variable.Name] = true
+	}
+	for _, nested := range step.Steps {
+		if err := validateStep(nested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package runscope
+
+import "testing"
+
+func TestVariableConstructors(t *testing.T) {
+	tests := []struct {
+		variable Variable
+		want     Variable
+	}{
+		{VariableFromJSON("user_id", "data.id"), Variable{Name: "user_id", Source: "response_json", Property: "data.id"}},
+		{VariableFromXML("user_id", "/user/id"), Variable{Name: "user_id", Source: "response_xml", Property: "/user/id"}},
+		{VariableFromHeader("location", "Location"), Variable{Name: "location", Source: "response_headers", Property: "Location"}},
+		{VariableFromStatus("status"), Variable{Name: "status", Source: "response_status"}},
+		{VariableFromCookie("session", "sessionid"), Variable{Name: "session", Source: "response_cookies", Property: "sessionid"}},
+		{VariableFromText("token", `token=(\w+)`), Variable{Name: "token", Source: "response_text", Property: `token=(\w+)`}},
+	}
+
+	for _, test := range tests {
+		testResponseData(t, test.variable, test.want)
+		if err := ValidateVariable(test.variable); err != nil {
+			t.Errorf("ValidateVariable(%+v) returned error: %v", test.variable, err)
+		}
+	}
+}
+
+func TestValidateVariable(t *testing.T) {
+	invalid := []Variable{
+		VariableFromJSON("", "data.id"),
+		VariableFromJSON("1user", "data.id"),
+		VariableFromJSON("user-id", "data.id"),
+		VariableFromJSON("user_id", ""),
+		VariableFromHeader("location", ""),
+		VariableFromText("token", `token=(\w+`),
+		{Name: "status", Source: "response_status", Property: "code"},
+		{Name: "body", Source: "response_body", Property: "x"},
+	}
+	for _, variable := range invalid {
+		if err := ValidateVariable(variable); err == nil {
+			t.Errorf("ValidateVariable(%+v) should return an error", variable)
+		}
+	}
+}
+
+func TestNewStepValidatesVariables(t *testing.T) {
+	setup()
+	defer teardown()
+
+	step := Step{
+		StepType:  "request",
+		Variables: []Variable{VariableFromJSON("user id", "data.id")},
+	}
+	if _, err := client.NewStep("1", "1", step); err == nil {
+		t.Error("NewStep should reject invalid variables before sending them")
+	}
+
+	step.Variables = []Variable{VariableFromJSON("user_id", "data.id"), VariableFromStatus("user_id")}
+	if _, err := client.UpdateStep("1", "1", "1", step); err == nil {
+		t.Error("UpdateStep should reject variables extracted more than once")
+	}
+}
@@ -0,0 +1,450 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlToJSON converts a YAML document to JSON so that it can be decoded
+// like any other definition. It supports the subset of YAML used by
+// configuration files: block mappings and sequences, plain and quoted
+// scalars, literal (|) and folded (>) block scalars, single line flow
+// collections and comments. Anchors, aliases, tags and multiple documents
+// are rejected.
+func yamlToJSON(data []byte) ([]byte, error) {
+	parser := &yamlParser{}
+	for i, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("YAML line %d: tabs are not allowed in indentation", i+1)
+		}
+		parser.lines = append(parser.lines, yamlLine{number: i + 1, indent: len(line) - len(text), text: text})
+	}
+
+	if i, ok := parser.next(); ok && parser.lines[i].indent == 0 && strings.TrimRight(parser.lines[i].text, " ") == "---" {
+		parser.pos = i + 1
+	}
+	value, err := parser.parseNode(0)
+	if err != nil {
+		return nil, err
+	}
+	if i, ok := parser.next(); ok {
+		return nil, parser.errorf(i, "unexpected %q", parser.lines[i].text)
+	}
+	return json.Marshal(value)
+}
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+var (
+	yamlInt   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlFloat = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+func (parser *yamlParser) errorf(i int, format string, args ...interface{}) error {
+	return fmt.Errorf("YAML line %d: %s", parser.lines[i].number, fmt.Sprintf(format, args...))
+}
+
+// next returns the index of the next line with content, skipping blank
+// lines and comments
+func (parser *yamlParser) next() (int, bool) {
+	for i := parser.pos; i < len(parser.lines); i++ {
+		text := strings.TrimSpace(parser.lines[i].text)
+		if text != "" && !strings.HasPrefix(text, "#") {
+			return i, true
+		}
+	}
+	return len(parser.lines), false
+}
+
+// parseNode parses the block starting at the next line, which must be
+// indented by at least indent spaces. A missing block is null.
+func (parser *yamlParser) parseNode(indent int) (interface{}, error) {
+	i, ok := parser.next()
+	if !ok || parser.lines[i].indent < indent {
+		return nil, nil
+	}
+	line := parser.lines[i]
+	if isYAMLSequenceItem(line.text) {
+		return parser.parseSequence(line.indent)
+	}
+	if _, _, ok, err := splitYAMLKey(line.text); ok || err != nil {
+		return parser.parseMapping(line.indent)
+	}
+
+	parser.pos = i + 1
+	value, err := parser.parseValue(i, line.text, line.indent)
+	if err != nil {
+		return nil, err
+	}
+	if j, ok := parser.next(); ok && parser.lines[j].indent > line.indent {
+		return nil, parser.errorf(j, "multi-line plain scalars are not supported, use a block scalar")
+	}
+	return value, nil
+}
+
+func (parser *yamlParser) parseSequence(indent int) (interface{}, error) {
+	items := []interface{}{}
+	for {
+		i, ok := parser.next()
+		if !ok || parser.lines[i].indent < indent {
+			return items, nil
+		}
+		line := parser.lines[i]
+		if line.indent > indent {
+			return nil, parser.errorf(i, "unexpected indentation")
+		}
+		if !isYAMLSequenceItem(line.text) {
+			return nil, parser.errorf(i, "expected a sequence item, found %q", line.text)
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		if rest == "" || strings.HasPrefix(rest, "#") {
+			parser.pos = i + 1
+			item, err := parser.parseNode(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		var item interface{}
+		var err error
+		if _, _, isKey, _ := splitYAMLKey(rest); isKey || isYAMLSequenceItem(rest) {
+			// a collection on the same line as its dash is parsed as a
+			// block starting at the column of its first character
+			offset := len(line.text) - len(rest)
+			parser.lines[i] = yamlLine{number: line.number, indent: indent + offset, text: rest}
+			item, err = parser.parseNode(indent + offset)
+		} else {
+			parser.pos = i + 1
+			item, err = parser.parseValue(i, rest, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
+
+func (parser *yamlParser) parseMapping(indent int) (interface{}, error) {
+	mapping := map[string]interface{}{}
+	for {
+		i, ok := parser.next()
+		if !ok || parser.lines[i].indent < indent {
+			return mapping, nil
+		}
+		line := parser.lines[i]
+		if line.indent > indent {
+			return nil, parser.errorf(i, "unexpected indentation")
+		}
+		if isYAMLSequenceItem(line.text) {
+			return nil, parser.errorf(i, "expected a mapping key, found %q", line.text)
+		}
+		key, rest, ok, err := splitYAMLKey(line.text)
+		if err != nil {
+			return nil, parser.errorf(i, "%v", err)
+		}
+		if !ok {
+			return nil, parser.errorf(i, "expected a mapping key, found %q", line.text)
+		}
+		if _, ok := mapping[key]; ok {
+			return nil, parser.errorf(i, "duplicate key %q", key)
+		}
+
+		parser.pos = i + 1
+		var value interface{}
+		if rest == "" || strings.HasPrefix(rest, "#") {
+			// a sequence may start at the same indentation as its key
+			if j, ok := parser.next(); ok && parser.lines[j].indent == indent && isYAMLSequenceItem(parser.lines[j].text) {
+				value, err = parser.parseSequence(indent)
+			} else {
+				value, err = parser.parseNode(indent + 1)
+			}
+		} else {
+			value, err = parser.parseValue(i, rest, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		mapping[key] = value
+	}
+}
+
+// parseValue parses a value that starts on line i, where a block scalar
+// continues on the lines indented by more than indent spaces
+func (parser *yamlParser) parseValue(i int, text string, indent int) (interface{}, error) {
+	switch text[0] {
+	case '|', '>':
+		return parser.parseBlockScalar(i, text, indent)
+	case '[', '{':
+		value, rest, err := parseYAMLFlow(text)
+		if err != nil {
+			return nil, parser.errorf(i, "%v", err)
+		}
+		if !isYAMLComment(rest) {
+			return nil, parser.errorf(i, "unexpected %q after flow collection", rest)
+		}
+		return value, nil
+	}
+
+	value, rest, err := parseYAMLScalar(text, "")
+	if err != nil {
+		return nil, parser.errorf(i, "%v", err)
+	}
+	if !isYAMLComment(rest) {
+		return nil, parser.errorf(i, "unexpected %q after value", rest)
+	}
+	return value, nil
+}
+
+// parseBlockScalar reads a literal or folded block scalar from the lines
+// following line i
+func (parser *yamlParser) parseBlockScalar(i int, header string, indent int) (interface{}, error) {
+	style, chomping := header[0], ""
+	header = strings.TrimSpace(header[1:])
+	if header != "" && (header[0] == '-' || header[0] == '+') {
+		chomping, header = header[:1], strings.TrimSpace(header[1:])
+	}
+	if !isYAMLComment(header) {
+		return nil, parser.errorf(i, "unsupported block scalar header %q", header)
+	}
+
+	var lines []string
+	contentIndent := -1
+	for parser.pos < len(parser.lines) {
+		line := parser.lines[parser.pos]
+		if strings.TrimSpace(line.text) == "" {
+			lines = append(lines, "")
+			parser.pos++
+			continue
+		}
+		if contentIndent == -1 {
+			if line.indent <= indent {
+				break
+			}
+			contentIndent = line.indent
+		}
+		if line.indent < contentIndent {
+			break
+		}
+		lines = append(lines, strings.Repeat(" ", line.indent-contentIndent)+line.text)
+		parser.pos++
+	}
+
+	// trailing blank lines belong to the block but are only kept with +
+	content := len(lines)
+	for content > 0 && lines[content-1] == "" {
+		content--
+	}
+	trailing := lines[content:]
+	lines = lines[:content]
+
+	var value string
+	if style == '|' {
+		value = strings.Join(lines, "\n")
+	} else {
+		for j, line := range lines {
+			switch {
+			case j == 0:
+			case line == "":
+				value += "\n"
+			case lines[j-1] != "":
+				value += " "
+			}
+			value += line
+		}
+	}
+
+	switch {
+	case chomping == "-" || len(lines) == 0:
+	case chomping == "+":
+		value += "\n" + strings.Repeat("\n", len(trailing))
+	default:
+		value += "\n"
+	}
+	return value, nil
+}
+
+// splitYAMLKey splits a mapping entry into its key and the rest of the
+// line, reporting false when the text is not a mapping entry
+func splitYAMLKey(text string) (string, string, bool, error) {
+	if text[0] == '"' || text[0] == '\'' {
+		key, rest, err := parseYAMLQuoted(text)
+		if err != nil {
+			return "", "", false, err
+		}
+		if rest == ":" || strings.HasPrefix(rest, ": ") {
+			return key, strings.TrimSpace(rest[1:]), true, nil
+		}
+		return "", "", false, nil
+	}
+	if text[0] == '[' || text[0] == '{' || text[0] == '#' {
+		return "", "", false, nil
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == '#' && i > 0 && text[i-1] == ' ' {
+			break
+		}
+		if text[i] == ':' && (i == len(text)-1 || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+// parseYAMLScalar parses a quoted or plain scalar at the start of text. A
+// plain scalar ends at a comment or at any of the given terminators.
+func parseYAMLScalar(text string, terminators string) (interface{}, string, error) {
+	if text[0] == '"' || text[0] == '\'' {
+		value, rest, err := parseYAMLQuoted(text)
+		return value, strings.TrimLeft(rest, " "), err
+	}
+	if strings.ContainsRune("&*!%@`", rune(text[0])) {
+		return nil, "", fmt.Errorf("anchors, aliases, tags and reserved indicators are not supported: %q", text)
+	}
+
+	end := len(text)
+	for i := 0; i < len(text); i++ {
+		if text[i] == '#' && i > 0 && text[i-1] == ' ' || strings.IndexByte(terminators, text[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+	return resolveYAMLScalar(strings.TrimSpace(text[:end])), text[end:], nil
+}
+
+// resolveYAMLScalar returns the null, boolean or number a plain scalar
+// represents, or the scalar itself
+func resolveYAMLScalar(text string) interface{} {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if yamlInt.MatchString(text) {
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+	}
+	if yamlFloat.MatchString(text) {
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	}
+	return text
+}
+
+// parseYAMLQuoted parses a single or double quoted string at the start of
+// text, returning the rest of the line after the closing quote
+func parseYAMLQuoted(text string) (string, string, error) {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case quote == '\'' && text[i] == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			if quote == '\'' {
+				return strings.ReplaceAll(text[1:i], "''", "'"), text[i+1:], nil
+			}
+			var value string
+			if err := json.Unmarshal([]byte(text[:i+1]), &value); err != nil {
+				return "", "", fmt.Errorf("invalid double quoted string %s", text[:i+1])
+			}
+			return value, text[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string %s", text)
+}
+
+// parseYAMLFlow parses a flow sequence or mapping at the start of text,
+// which must end on the same line
+func parseYAMLFlow(text string) (interface{}, string, error) {
+	closing := byte(']')
+	if text[0] == '{' {
+		closing = '}'
+	}
+	sequence := []interface{}{}
+	mapping := map[string]interface{}{}
+
+	rest := strings.TrimLeft(text[1:], " ")
+	for {
+		if rest == "" {
+			return nil, "", fmt.Errorf("unterminated flow collection %s", text)
+		}
+		if rest[0] == closing {
+			rest = strings.TrimLeft(rest[1:], " ")
+			if closing == '}' {
+				return mapping, rest, nil
+			}
+			return sequence, rest, nil
+		}
+
+		var key string
+		if closing == '}' {
+			value, after, err := parseYAMLScalar(rest, ":,}")
+			if err != nil {
+				return nil, "", err
+			}
+			if !strings.HasPrefix(after, ":") {
+				return nil, "", fmt.Errorf("expected ':' after key in %s", text)
+			}
+			key = fmt.Sprint(value)
+			if _, ok := mapping[key]; ok {
+				return nil, "", fmt.Errorf("duplicate key %q", key)
+			}
+			rest = strings.TrimLeft(after[1:], " ")
+		}
+
+		var value interface{}
+		var err error
+		if rest != "" && (rest[0] == '[' || rest[0] == '{') {
+			value, rest, err = parseYAMLFlow(rest)
+		} else if rest != "" {
+			value, rest, err = parseYAMLScalar(rest, ",]}")
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if closing == '}' {
+			mapping[key] = value
+		} else {
+			sequence = append(sequence, value)
+		}
+
+		rest = strings.TrimLeft(rest, " ")
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimLeft(rest[1:], " ")
+		} else if rest == "" || rest[0] != closing {
+			return nil, "", fmt.Errorf("expected ',' or '%c' in %s", closing, text)
+		}
+	}
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isYAMLComment(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "" || strings.HasPrefix(text, "#")
+}
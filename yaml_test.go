@@ -0,0 +1,61 @@
+package runscope
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want string
+	}{
+		{"name: Login\ncount: 2\nratio: 0.5\nenabled: true\nnote: ~\n", `{"count":2,"enabled":true,"name":"Login","note":null,"ratio":0.5}`},
+		{"---\n# buckets\nbuckets:\n  - name: A # first\n    key: abc\n  - name: B\n", `{"buckets":[{"key":"abc","name":"A"},{"name":"B"}]}`},
+		{"regions:\n- us1\n- eu1\n", `{"regions":["us1","eu1"]}`},
+		{"regions: [us1, \"eu1\"]\nvars: {baseUrl: 'https://example.com', n: 1}\nempty: []\n", `{"empty":[],"regions":["us1","eu1"],"vars":{"baseUrl":"https://example.com","n":1}}`},
+		{"url: https://example.com/a#b\nquoted: \"a # b\\n\"\nsingle: 'it''s'\n\"key: x\": 1\n", `{"key: x":1,"quoted":"a # b\n","single":"it's","url":"https://example.com/a#b"}`},
+		{"- - 1\n  - 2\n- x\n", `[[1,2],"x"]`},
+		{"script: |\n  a\n    b\n\n  c\nnext: 1\n", `{"next":1,"script":"a\n  b\n\nc\n"}`},
+		{"strip: |-\n  a\n  b\nfolded: >\n  a\n  b\n\n  c\n", `{"folded":"a b\nc\n","strip":"a\nb"}`},
+		{"steps:\n  - |\n    body\n  - >-\n    x\n    y\n", `{"steps":["body\n","x y"]}`},
+		{"nested:\n  value:\n", `{"nested":{"value":null}}`},
+		{"", `null`},
+	}
+
+	for _, test := range tests {
+		got, err := yamlToJSON([]byte(test.yaml))
+		if err != nil {
+			t.Errorf("yamlToJSON(%q) returned error: %v", test.yaml, err)
+			continue
+		}
+		var gotValue, wantValue interface{}
+		json.Unmarshal(got, &gotValue)
+		json.Unmarshal([]byte(test.want), &wantValue)
+		if !reflect.DeepEqual(gotValue, wantValue) {
+			t.Errorf("yamlToJSON(%q) returned %s, want %s", test.yaml, got, test.want)
+		}
+	}
+}
+
+func TestYAMLToJSONInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"tab indentation":     "a:\n\tb: 1\n",
+		"bad indentation":     "a: 1\n  b: 2\n",
+		"duplicate key":       "a: 1\na: 2\n",
+		"multi-line scalar":   "a:\n  b\n  c\n",
+		"mixed collection":    "a: 1\n- b\n",
+		"unterminated string": "a: \"b\n",
+		"unterminated flow":   "a: [b, c\n",
+		"anchor":              "a: &anchor b\n",
+		"alias":               "a: *anchor\n",
+		"text after value":    "a: \"b\" c\n",
+	}
+
+	for name, data := range invalid {
+		if _, err := yamlToJSON([]byte(data)); err == nil {
+			t.Errorf("%s: expected yamlToJSON to return an error", name)
+		}
+	}
+}